@@ -0,0 +1,272 @@
+package gosu
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// APIMod is a mod as represented by lazer, with optional per-mod settings.
+type APIMod struct {
+	Acronym  string                 `json:"acronym"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+type RateAdjustSettings struct {
+	SpeedChange *float64 `json:"speed_change,omitempty"`
+	AdjustPitch *bool    `json:"adjust_pitch,omitempty"`
+}
+
+type RampSettings struct {
+	InitialRate *float64 `json:"initial_rate,omitempty"`
+	FinalRate   *float64 `json:"final_rate,omitempty"`
+	AdjustPitch *bool    `json:"adjust_pitch,omitempty"`
+}
+
+type AdaptiveSpeedSettings struct {
+	InitialRate *float64 `json:"initial_rate,omitempty"`
+	AdjustPitch *bool    `json:"adjust_pitch,omitempty"`
+}
+
+type DifficultyAdjustSettings struct {
+	CircleSize        *float64 `json:"circle_size,omitempty"`
+	ApproachRate      *float64 `json:"approach_rate,omitempty"`
+	DrainRate         *float64 `json:"drain_rate,omitempty"`
+	OverallDifficulty *float64 `json:"overall_difficulty,omitempty"`
+	ScrollSpeed       *float64 `json:"scroll_speed,omitempty"`
+	HardRockOffsets   *bool    `json:"hard_rock_offsets,omitempty"`
+	ExtendedLimits    *bool    `json:"extended_limits,omitempty"`
+}
+
+type EasySettings struct {
+	Retries *int `json:"retries,omitempty"`
+}
+
+type HiddenSettings struct {
+	OnlyFadeApproachCircles *bool `json:"only_fade_approach_circles,omitempty"`
+}
+
+type FlashlightSettings struct {
+	SizeMultiplier *float64 `json:"size_multiplier,omitempty"`
+	ComboBasedSize *bool    `json:"combo_based_size,omitempty"`
+	FollowDelay    *float64 `json:"follow_delay,omitempty"`
+}
+
+type SuddenDeathSettings struct {
+	Restart *bool `json:"restart,omitempty"`
+}
+
+type ClassicSettings struct {
+	NoSliderHeadAccuracy *bool `json:"no_slider_head_accuracy,omitempty"`
+	ClassicNoteLock      *bool `json:"classic_note_lock,omitempty"`
+	AlwaysPlayTailSample *bool `json:"always_play_tail_sample,omitempty"`
+	FadeHitCircleEarly   *bool `json:"fade_hit_circle_early,omitempty"`
+	ClassicHealth        *bool `json:"classic_health,omitempty"`
+}
+
+type RandomSettings struct {
+	Seed           *int     `json:"seed,omitempty"`
+	AngleSharpness *float64 `json:"angle_sharpness,omitempty"`
+}
+
+type TargetPracticeSettings struct {
+	Seed      *int  `json:"seed,omitempty"`
+	Metronome *bool `json:"metronome,omitempty"`
+}
+
+type MirrorSettings struct {
+	Reflection *string `json:"reflection,omitempty"`
+}
+
+type MutedSettings struct {
+	InverseMuting    *bool `json:"inverse_muting,omitempty"`
+	EnableMetronome  *bool `json:"enable_metronome,omitempty"`
+	MuteComboCount   *int  `json:"mute_combo_count,omitempty"`
+	AffectsHitSounds *bool `json:"affects_hit_sounds,omitempty"`
+}
+
+// apiModSettings returns an empty settings value of the type used by each mod acronym.
+var apiModSettings = map[string]func() interface{}{
+	"DT": func() interface{} { return &RateAdjustSettings{} },
+	"NC": func() interface{} { return &RateAdjustSettings{} },
+	"HT": func() interface{} { return &RateAdjustSettings{} },
+	"DC": func() interface{} { return &RateAdjustSettings{} },
+	"WU": func() interface{} { return &RampSettings{} },
+	"WD": func() interface{} { return &RampSettings{} },
+	"AS": func() interface{} { return &AdaptiveSpeedSettings{} },
+	"DA": func() interface{} { return &DifficultyAdjustSettings{} },
+	"EZ": func() interface{} { return &EasySettings{} },
+	"HD": func() interface{} { return &HiddenSettings{} },
+	"FL": func() interface{} { return &FlashlightSettings{} },
+	"SD": func() interface{} { return &SuddenDeathSettings{} },
+	"PF": func() interface{} { return &SuddenDeathSettings{} },
+	"CL": func() interface{} { return &ClassicSettings{} },
+	"RD": func() interface{} { return &RandomSettings{} },
+	"TP": func() interface{} { return &TargetPracticeSettings{} },
+	"MR": func() interface{} { return &MirrorSettings{} },
+	"MU": func() interface{} { return &MutedSettings{} },
+}
+
+// legacyAPIModAcronyms maps legacy mods whose lazer acronym differs from their legacy one.
+var legacyAPIModAcronyms = map[Mod]string{
+	K1: "1K",
+	K2: "2K",
+	K3: "3K",
+	K4: "4K",
+	K5: "5K",
+	K6: "6K",
+	K7: "7K",
+	K8: "8K",
+	K9: "9K",
+	RN: "RD",
+	TR: "TP",
+	KC: "DS",
+}
+
+var apiModLegacyValues = func() map[string]Mod {
+	values := make(map[string]Mod, len(modStrings))
+	for mod, acronym := range modStrings {
+		if apiAcronym, ok := legacyAPIModAcronyms[mod]; ok {
+			acronym = apiAcronym
+		}
+		values[acronym] = mod
+	}
+	return values
+}()
+
+// NewAPIMod creates a mod with settings taken from one of the typed settings structs, which may be nil.
+func NewAPIMod(acronym string, settings interface{}) (APIMod, error) {
+	mod := APIMod{Acronym: acronym}
+
+	if settings == nil {
+		return mod, nil
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return mod, err
+	}
+
+	if err := json.Unmarshal(data, &mod.Settings); err != nil {
+		return mod, err
+	}
+
+	if len(mod.Settings) == 0 {
+		mod.Settings = nil
+	}
+
+	return mod, nil
+}
+
+// DecodeSettings decodes the mod's settings into v, usually a pointer to one of the typed settings structs.
+func (m APIMod) DecodeSettings(v interface{}) error {
+	if m.Settings == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(m.Settings)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// TypedSettings returns the mod's settings as a pointer to the typed settings struct for its acronym,
+// or nil for mods without known settings.
+func (m APIMod) TypedSettings() (interface{}, error) {
+	newSettings, ok := apiModSettings[m.Acronym]
+	if !ok {
+		return nil, nil
+	}
+
+	settings := newSettings()
+	if err := m.DecodeSettings(settings); err != nil {
+		return nil, fmt.Errorf("decoding %s settings: %w", m.Acronym, err)
+	}
+
+	return settings, nil
+}
+
+// Legacy returns the legacy bitmask equivalent of the mod, and false if it has none.
+// Settings are not carried over, so a DT with a custom speed maps to a plain DT.
+func (m APIMod) Legacy() (Mod, bool) {
+	mod, ok := apiModLegacyValues[m.Acronym]
+	if !ok {
+		return 0, false
+	}
+
+	return mod.Normalize(), true
+}
+
+// APIMods converts a legacy bitmask to lazer mods. Implied mods such as DT under NC are dropped.
+func (m Mod) APIMods() []APIMod {
+	if m&NC != 0 {
+		m &^= DT
+	}
+
+	if m&PF != 0 {
+		m &^= SD
+	}
+
+	var result []APIMod
+
+	for i := NF; i <= MR; i <<= 1 {
+		if m&i == 0 {
+			continue
+		}
+
+		acronym := modStrings[i]
+		if apiAcronym, ok := legacyAPIModAcronyms[i]; ok {
+			acronym = apiAcronym
+		}
+
+		result = append(result, APIMod{Acronym: acronym})
+	}
+
+	return result
+}
+
+// ModsFromAPIMods converts lazer mods to a legacy bitmask, returning the mods that have no legacy equivalent separately.
+func ModsFromAPIMods(mods []APIMod) (Mod, []APIMod) {
+	var result Mod
+	var remaining []APIMod
+
+	for _, mod := range mods {
+		legacy, ok := mod.Legacy()
+		if !ok {
+			remaining = append(remaining, mod)
+			continue
+		}
+		result |= legacy
+	}
+
+	return result, remaining
+}
+
+// APIModsClockRate returns the playback speed of lazer mods, taking custom speed_change settings into account.
+func APIModsClockRate(mods []APIMod) float64 {
+	rate := 1.0
+
+	for _, mod := range mods {
+		var settings RateAdjustSettings
+
+		switch mod.Acronym {
+		case "DT", "NC":
+			settings.SpeedChange = ptr(1.5)
+		case "HT", "DC":
+			settings.SpeedChange = ptr(0.75)
+		default:
+			continue
+		}
+
+		if err := mod.DecodeSettings(&settings); err == nil && settings.SpeedChange != nil {
+			rate *= *settings.SpeedChange
+		}
+	}
+
+	return rate
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
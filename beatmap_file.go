@@ -0,0 +1,636 @@
+package gosu
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type HitObjectType int
+
+const (
+	HitObjectCircle   HitObjectType = 1 << 0
+	HitObjectSlider   HitObjectType = 1 << 1
+	HitObjectNewCombo HitObjectType = 1 << 2
+	HitObjectSpinner  HitObjectType = 1 << 3
+	HitObjectHold     HitObjectType = 1 << 7
+)
+
+// hitObjectComboSkip is the mask of the three bits that store how many combo colours to skip.
+const hitObjectComboSkip HitObjectType = 0b111 << 4
+
+type CurveType string
+
+const (
+	CurveTypeBezier        CurveType = "B"
+	CurveTypeCatmull       CurveType = "C"
+	CurveTypeLinear        CurveType = "L"
+	CurveTypePerfectCircle CurveType = "P"
+)
+
+type BeatmapEventType string
+
+const (
+	BeatmapEventBackground BeatmapEventType = "0"
+	BeatmapEventVideo      BeatmapEventType = "1"
+	BeatmapEventBreak      BeatmapEventType = "2"
+)
+
+var ErrInvalidBeatmapFile = errors.New("invalid beatmap file")
+
+type BeatmapFileGeneral struct {
+	AudioFilename        string
+	AudioLeadIn          int
+	PreviewTime          int
+	Countdown            int
+	SampleSet            string
+	StackLeniency        float64
+	Mode                 Ruleset
+	LetterboxInBreaks    bool
+	UseSkinSprites       bool
+	EpilepsyWarning      bool
+	SpecialStyle         bool
+	WidescreenStoryboard bool
+}
+
+type BeatmapFileMetadata struct {
+	Title         string
+	TitleUnicode  string
+	Artist        string
+	ArtistUnicode string
+	Creator       string
+	Version       string
+	Source        string
+	Tags          []string
+	BeatmapID     int
+	BeatmapsetID  int
+}
+
+type BeatmapFileDifficulty struct {
+	HPDrainRate       float64
+	CircleSize        float64
+	OverallDifficulty float64
+	ApproachRate      float64
+	SliderMultiplier  float64
+	SliderTickRate    float64
+}
+
+type BeatmapEvent struct {
+	Type      BeatmapEventType
+	StartTime int
+	// EndTime is only set for breaks.
+	EndTime int
+	// Filename is only set for backgrounds and videos.
+	Filename string
+	Params   []string
+}
+
+type TimingPoint struct {
+	Time        float64
+	BeatLength  float64
+	Meter       int
+	SampleSet   int
+	SampleIndex int
+	Volume      int
+	Uninherited bool
+	Effects     int
+}
+
+// BPM returns the tempo of an uninherited timing point.
+func (t TimingPoint) BPM() float64 {
+	return 60000 / t.BeatLength
+}
+
+// SliderVelocity returns the slider velocity multiplier of an inherited timing point.
+func (t TimingPoint) SliderVelocity() float64 {
+	if t.Uninherited || t.BeatLength >= 0 {
+		return 1
+	}
+	return -100 / t.BeatLength
+}
+
+type Colour struct {
+	R int
+	G int
+	B int
+}
+
+type BeatmapFileColours struct {
+	Combo               []Colour
+	SliderTrackOverride *Colour
+	SliderBorder        *Colour
+}
+
+type CurvePoint struct {
+	X int
+	Y int
+}
+
+type SliderParams struct {
+	CurveType   CurveType
+	CurvePoints []CurvePoint
+	Slides      int
+	Length      float64
+	EdgeSounds  []int
+	EdgeSets    []string
+}
+
+type HitObject struct {
+	X        int
+	Y        int
+	Time     int
+	Type     HitObjectType
+	HitSound int
+	Slider   *SliderParams
+	// EndTime is only set for spinners and mania holds.
+	EndTime   int
+	HitSample string
+}
+
+func (h HitObject) IsCircle() bool {
+	return h.Type&HitObjectCircle != 0
+}
+
+func (h HitObject) IsSlider() bool {
+	return h.Type&HitObjectSlider != 0
+}
+
+func (h HitObject) IsSpinner() bool {
+	return h.Type&HitObjectSpinner != 0
+}
+
+func (h HitObject) IsHold() bool {
+	return h.Type&HitObjectHold != 0
+}
+
+func (h HitObject) IsNewCombo() bool {
+	return h.Type&HitObjectNewCombo != 0
+}
+
+// ComboSkip returns how many combo colours are skipped when this object starts a new combo.
+func (h HitObject) ComboSkip() int {
+	return int(h.Type&hitObjectComboSkip) >> 4
+}
+
+// BeatmapFile is a parsed .osu file.
+type BeatmapFile struct {
+	FormatVersion int
+	// MD5 is the checksum of the file contents, as used by the API.
+	MD5          string
+	General      BeatmapFileGeneral
+	Metadata     BeatmapFileMetadata
+	Difficulty   BeatmapFileDifficulty
+	Events       []BeatmapEvent
+	TimingPoints []TimingPoint
+	Colours      BeatmapFileColours
+	HitObjects   []HitObject
+}
+
+// ParseBeatmapFile reads a beatmap in the .osu format.
+func ParseBeatmapFile(r io.Reader) (*BeatmapFile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := md5.Sum(data)
+	file := &BeatmapFile{MD5: hex.EncodeToString(checksum[:])}
+	file.Difficulty.ApproachRate = -1
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+
+	section := ""
+	first := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+
+		if version, ok := strings.CutPrefix(trimmed, "osu file format v"); ok {
+			file.FormatVersion, err = strconv.Atoi(version)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidBeatmapFile, err)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section = trimmed[1 : len(trimmed)-1]
+			continue
+		}
+
+		switch section {
+		case "General":
+			err = file.parseGeneral(trimmed)
+		case "Metadata":
+			err = file.parseMetadata(trimmed)
+		case "Difficulty":
+			err = file.parseDifficulty(trimmed)
+		case "Events":
+			// Indented lines are storyboard commands belonging to the previous object.
+			if line[0] != ' ' && line[0] != '_' {
+				err = file.parseEvent(trimmed)
+			}
+		case "TimingPoints":
+			err = file.parseTimingPoint(trimmed)
+		case "Colours":
+			err = file.parseColour(trimmed)
+		case "HitObjects":
+			err = file.parseHitObject(trimmed)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBeatmapFile, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Old formats have no approach rate and use the overall difficulty instead.
+	if file.Difficulty.ApproachRate < 0 {
+		file.Difficulty.ApproachRate = file.Difficulty.OverallDifficulty
+	}
+
+	return file, nil
+}
+
+// Lookup returns a request that looks up the beatmap on the API using the file's checksum.
+func (f *BeatmapFile) Lookup(c *Client) *LookupBeatmapRequest {
+	return c.LookupBeatmap().SetChecksum(f.MD5)
+}
+
+func splitKeyValue(line string) (string, string) {
+	key, value, _ := strings.Cut(line, ":")
+	return strings.TrimSpace(key), strings.TrimSpace(value)
+}
+
+func (f *BeatmapFile) parseGeneral(line string) error {
+	key, value := splitKeyValue(line)
+
+	var err error
+	switch key {
+	case "AudioFilename":
+		f.General.AudioFilename = value
+	case "AudioLeadIn":
+		f.General.AudioLeadIn, err = strconv.Atoi(value)
+	case "PreviewTime":
+		f.General.PreviewTime, err = strconv.Atoi(value)
+	case "Countdown":
+		f.General.Countdown, err = strconv.Atoi(value)
+	case "SampleSet":
+		f.General.SampleSet = value
+	case "StackLeniency":
+		f.General.StackLeniency, err = strconv.ParseFloat(value, 64)
+	case "Mode":
+		var mode int
+		mode, err = strconv.Atoi(value)
+		f.General.Mode = Ruleset(mode)
+	case "LetterboxInBreaks":
+		f.General.LetterboxInBreaks = value == "1"
+	case "UseSkinSprites":
+		f.General.UseSkinSprites = value == "1"
+	case "EpilepsyWarning":
+		f.General.EpilepsyWarning = value == "1"
+	case "SpecialStyle":
+		f.General.SpecialStyle = value == "1"
+	case "WidescreenStoryboard":
+		f.General.WidescreenStoryboard = value == "1"
+	}
+
+	return err
+}
+
+func (f *BeatmapFile) parseMetadata(line string) error {
+	key, value := splitKeyValue(line)
+
+	var err error
+	switch key {
+	case "Title":
+		f.Metadata.Title = value
+	case "TitleUnicode":
+		f.Metadata.TitleUnicode = value
+	case "Artist":
+		f.Metadata.Artist = value
+	case "ArtistUnicode":
+		f.Metadata.ArtistUnicode = value
+	case "Creator":
+		f.Metadata.Creator = value
+	case "Version":
+		f.Metadata.Version = value
+	case "Source":
+		f.Metadata.Source = value
+	case "Tags":
+		f.Metadata.Tags = strings.Fields(value)
+	case "BeatmapID":
+		f.Metadata.BeatmapID, err = strconv.Atoi(value)
+	case "BeatmapSetID":
+		f.Metadata.BeatmapsetID, err = strconv.Atoi(value)
+	}
+
+	return err
+}
+
+func (f *BeatmapFile) parseDifficulty(line string) error {
+	key, value := splitKeyValue(line)
+
+	var target *float64
+	switch key {
+	case "HPDrainRate":
+		target = &f.Difficulty.HPDrainRate
+	case "CircleSize":
+		target = &f.Difficulty.CircleSize
+	case "OverallDifficulty":
+		target = &f.Difficulty.OverallDifficulty
+	case "ApproachRate":
+		target = &f.Difficulty.ApproachRate
+	case "SliderMultiplier":
+		target = &f.Difficulty.SliderMultiplier
+	case "SliderTickRate":
+		target = &f.Difficulty.SliderTickRate
+	default:
+		return nil
+	}
+
+	var err error
+	*target, err = strconv.ParseFloat(value, 64)
+	return err
+}
+
+func (f *BeatmapFile) parseEvent(line string) error {
+	parts := strings.Split(line, ",")
+	if len(parts) < 2 {
+		return fmt.Errorf("malformed event %q", line)
+	}
+
+	event := BeatmapEvent{Type: BeatmapEventType(parts[0]), Params: parts[2:]}
+
+	// Events that are not backgrounds, videos or breaks are storyboard objects without a start time.
+	switch event.Type {
+	case "Background":
+		event.Type = BeatmapEventBackground
+	case "Video":
+		event.Type = BeatmapEventVideo
+	case "Break":
+		event.Type = BeatmapEventBreak
+	case BeatmapEventBackground, BeatmapEventVideo, BeatmapEventBreak:
+	default:
+		event.Params = parts[1:]
+		f.Events = append(f.Events, event)
+		return nil
+	}
+
+	startTime, err := parseIntOrFloat(parts[1])
+	if err != nil {
+		return err
+	}
+	event.StartTime = startTime
+
+	switch event.Type {
+	case BeatmapEventBackground, BeatmapEventVideo:
+		if len(parts) > 2 {
+			event.Filename = strings.Trim(parts[2], "\"")
+		}
+	case BeatmapEventBreak:
+		if len(parts) < 3 {
+			return fmt.Errorf("malformed break %q", line)
+		}
+		event.EndTime, err = parseIntOrFloat(parts[2])
+		if err != nil {
+			return err
+		}
+	}
+
+	f.Events = append(f.Events, event)
+	return nil
+}
+
+func (f *BeatmapFile) parseTimingPoint(line string) error {
+	parts := strings.Split(line, ",")
+	if len(parts) < 2 {
+		return fmt.Errorf("malformed timing point %q", line)
+	}
+
+	// Defaults for fields missing from old formats.
+	point := TimingPoint{Meter: 4, Volume: 100, Uninherited: true}
+
+	var err error
+	if point.Time, err = strconv.ParseFloat(parts[0], 64); err != nil {
+		return err
+	}
+
+	if point.BeatLength, err = strconv.ParseFloat(parts[1], 64); err != nil {
+		return err
+	}
+
+	ints := []*int{&point.Meter, &point.SampleSet, &point.SampleIndex, &point.Volume}
+	for i, target := range ints {
+		if len(parts) > i+2 {
+			if *target, err = strconv.Atoi(parts[i+2]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(parts) > 6 {
+		point.Uninherited = parts[6] == "1"
+	}
+
+	if len(parts) > 7 {
+		if point.Effects, err = strconv.Atoi(parts[7]); err != nil {
+			return err
+		}
+	}
+
+	f.TimingPoints = append(f.TimingPoints, point)
+	return nil
+}
+
+func (f *BeatmapFile) parseColour(line string) error {
+	key, value := splitKeyValue(line)
+
+	parts := strings.Split(value, ",")
+	if len(parts) < 3 {
+		return fmt.Errorf("malformed colour %q", line)
+	}
+
+	var colour Colour
+	var err error
+	for i, target := range []*int{&colour.R, &colour.G, &colour.B} {
+		if *target, err = strconv.Atoi(strings.TrimSpace(parts[i])); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(key, "Combo"):
+		f.Colours.Combo = append(f.Colours.Combo, colour)
+	case key == "SliderTrackOverride":
+		f.Colours.SliderTrackOverride = &colour
+	case key == "SliderBorder":
+		f.Colours.SliderBorder = &colour
+	}
+
+	return nil
+}
+
+func (f *BeatmapFile) parseHitObject(line string) error {
+	parts := strings.Split(line, ",")
+	if len(parts) < 5 {
+		return fmt.Errorf("malformed hit object %q", line)
+	}
+
+	var object HitObject
+	var err error
+
+	if object.X, err = parseIntOrFloat(parts[0]); err != nil {
+		return err
+	}
+
+	if object.Y, err = parseIntOrFloat(parts[1]); err != nil {
+		return err
+	}
+
+	if object.Time, err = parseIntOrFloat(parts[2]); err != nil {
+		return err
+	}
+
+	objectType, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return err
+	}
+	object.Type = HitObjectType(objectType)
+
+	if object.HitSound, err = strconv.Atoi(parts[4]); err != nil {
+		return err
+	}
+
+	params := parts[5:]
+
+	switch {
+	case object.IsSlider():
+		if len(params) < 3 {
+			return fmt.Errorf("malformed slider %q", line)
+		}
+
+		object.Slider, err = parseSliderParams(params)
+		if err != nil {
+			return err
+		}
+
+		if len(params) > 5 {
+			object.HitSample = params[5]
+		}
+	case object.IsSpinner():
+		if len(params) < 1 {
+			return fmt.Errorf("malformed spinner %q", line)
+		}
+
+		if object.EndTime, err = parseIntOrFloat(params[0]); err != nil {
+			return err
+		}
+
+		if len(params) > 1 {
+			object.HitSample = params[1]
+		}
+	case object.IsHold():
+		if len(params) < 1 {
+			return fmt.Errorf("malformed hold %q", line)
+		}
+
+		endTime, hitSample, _ := strings.Cut(params[0], ":")
+		if object.EndTime, err = parseIntOrFloat(endTime); err != nil {
+			return err
+		}
+		object.HitSample = hitSample
+	default:
+		if len(params) > 0 {
+			object.HitSample = params[0]
+		}
+	}
+
+	f.HitObjects = append(f.HitObjects, object)
+	return nil
+}
+
+func parseSliderParams(params []string) (*SliderParams, error) {
+	slider := &SliderParams{}
+
+	curve := strings.Split(params[0], "|")
+	slider.CurveType = CurveType(curve[0])
+
+	for _, point := range curve[1:] {
+		x, y, ok := strings.Cut(point, ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed curve point %q", point)
+		}
+
+		px, err := parseIntOrFloat(x)
+		if err != nil {
+			return nil, err
+		}
+
+		py, err := parseIntOrFloat(y)
+		if err != nil {
+			return nil, err
+		}
+
+		slider.CurvePoints = append(slider.CurvePoints, CurvePoint{X: px, Y: py})
+	}
+
+	var err error
+	if slider.Slides, err = strconv.Atoi(params[1]); err != nil {
+		return nil, err
+	}
+
+	if slider.Length, err = strconv.ParseFloat(params[2], 64); err != nil {
+		return nil, err
+	}
+
+	if len(params) > 3 && params[3] != "" {
+		for _, sound := range strings.Split(params[3], "|") {
+			edgeSound, err := strconv.Atoi(sound)
+			if err != nil {
+				return nil, err
+			}
+			slider.EdgeSounds = append(slider.EdgeSounds, edgeSound)
+		}
+	}
+
+	if len(params) > 4 && params[4] != "" {
+		slider.EdgeSets = strings.Split(params[4], "|")
+	}
+
+	return slider, nil
+}
+
+// parseIntOrFloat parses integer fields that some editors write with a fractional part.
+func parseIntOrFloat(value string) (int, error) {
+	value = strings.TrimSpace(value)
+
+	if i, err := strconv.Atoi(value); err == nil {
+		return i, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(f), nil
+}
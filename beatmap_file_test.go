@@ -0,0 +1,245 @@
+package gosu
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testBeatmapFile = "\ufeffosu file format v14\r\n" + `
+[General]
+AudioFilename: audio.mp3
+AudioLeadIn: 0
+PreviewTime: 61234
+Countdown: 0
+SampleSet: Soft
+StackLeniency: 0.7
+Mode: 0
+LetterboxInBreaks: 1
+WidescreenStoryboard: 1
+
+[Editor]
+DistanceSpacing: 1.2
+
+[Metadata]
+Title:Test Song
+TitleUnicode:テスト
+Artist:Test Artist
+ArtistUnicode:Test Artist
+Creator:peppy
+Version:Insane
+Source:
+Tags:tag1 tag2  tag3
+BeatmapID:123
+BeatmapSetID:45
+
+[Difficulty]
+HPDrainRate:5
+CircleSize:4
+OverallDifficulty:8
+ApproachRate:9.3
+SliderMultiplier:1.8
+SliderTickRate:1
+
+[Events]
+//Background and Video events
+0,0,"bg.jpg",0,0
+Video,500,"video.mp4"
+//Break Periods
+2,10000,15000
+Sprite,Foreground,Centre,"sb/star.png",320,240
+ F,0,1000,2000,0,1
+
+[TimingPoints]
+100,333.333333333333,4,2,1,60,1,0
+1000,-50,4,2,1,60,0,1
+
+[Colours]
+Combo1 : 255,128,64
+Combo2 : 0,0,0
+SliderBorder : 10,20,30
+
+[HitObjects]
+256,192,1000,5,0,0:0:0:0:
+100,100,2000,2,2,B|200:200|300:100,2,280.5,2|0|8,1:0|0:0|2:1,0:0:0:0:
+256,192,3000,12,0,4000,0:0:0:0:
+`
+
+func TestParseBeatmapFile(t *testing.T) {
+	file, err := ParseBeatmapFile(strings.NewReader(testBeatmapFile))
+	if err != nil {
+		t.Fatalf("ParseBeatmapFile() error = %v", err)
+	}
+
+	if file.FormatVersion != 14 {
+		t.Errorf("FormatVersion = %d, want 14", file.FormatVersion)
+	}
+
+	if len(file.MD5) != 32 {
+		t.Errorf("MD5 = %q, want a hex checksum", file.MD5)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"General", file.General, BeatmapFileGeneral{
+			AudioFilename:        "audio.mp3",
+			PreviewTime:          61234,
+			SampleSet:            "Soft",
+			StackLeniency:        0.7,
+			Mode:                 RulesetOsu,
+			LetterboxInBreaks:    true,
+			WidescreenStoryboard: true,
+		}},
+		{"Metadata", file.Metadata, BeatmapFileMetadata{
+			Title:         "Test Song",
+			TitleUnicode:  "テスト",
+			Artist:        "Test Artist",
+			ArtistUnicode: "Test Artist",
+			Creator:       "peppy",
+			Version:       "Insane",
+			Tags:          []string{"tag1", "tag2", "tag3"},
+			BeatmapID:     123,
+			BeatmapsetID:  45,
+		}},
+		{"Difficulty", file.Difficulty, BeatmapFileDifficulty{
+			HPDrainRate:       5,
+			CircleSize:        4,
+			OverallDifficulty: 8,
+			ApproachRate:      9.3,
+			SliderMultiplier:  1.8,
+			SliderTickRate:    1,
+		}},
+		{"Events", file.Events, []BeatmapEvent{
+			{Type: BeatmapEventBackground, Filename: "bg.jpg", Params: []string{`"bg.jpg"`, "0", "0"}},
+			{Type: BeatmapEventVideo, StartTime: 500, Filename: "video.mp4", Params: []string{`"video.mp4"`}},
+			{Type: BeatmapEventBreak, StartTime: 10000, EndTime: 15000, Params: []string{"15000"}},
+			{Type: "Sprite", Params: []string{"Foreground", "Centre", `"sb/star.png"`, "320", "240"}},
+		}},
+		{"TimingPoints", file.TimingPoints, []TimingPoint{
+			{Time: 100, BeatLength: 333.333333333333, Meter: 4, SampleSet: 2, SampleIndex: 1, Volume: 60, Uninherited: true},
+			{Time: 1000, BeatLength: -50, Meter: 4, SampleSet: 2, SampleIndex: 1, Volume: 60, Effects: 1},
+		}},
+		{"Colours", file.Colours, BeatmapFileColours{
+			Combo:        []Colour{{R: 255, G: 128, B: 64}, {}},
+			SliderBorder: &Colour{R: 10, G: 20, B: 30},
+		}},
+		{"HitObjects", file.HitObjects, []HitObject{
+			{X: 256, Y: 192, Time: 1000, Type: HitObjectCircle | HitObjectNewCombo, HitSample: "0:0:0:0:"},
+			{X: 100, Y: 100, Time: 2000, Type: HitObjectSlider, HitSound: 2, HitSample: "0:0:0:0:", Slider: &SliderParams{
+				CurveType:   CurveTypeBezier,
+				CurvePoints: []CurvePoint{{X: 200, Y: 200}, {X: 300, Y: 100}},
+				Slides:      2,
+				Length:      280.5,
+				EdgeSounds:  []int{2, 0, 8},
+				EdgeSets:    []string{"1:0", "0:0", "2:1"},
+			}},
+			{X: 256, Y: 192, Time: 3000, Type: HitObjectSpinner | HitObjectNewCombo, EndTime: 4000, HitSample: "0:0:0:0:"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("%s = %+v, want %+v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBeatmapFileSections(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, file *BeatmapFile)
+	}{
+		{
+			name:  "approach rate defaults to overall difficulty",
+			input: "osu file format v5\n[Difficulty]\nOverallDifficulty:7\n",
+			check: func(t *testing.T, file *BeatmapFile) {
+				if file.Difficulty.ApproachRate != 7 {
+					t.Errorf("ApproachRate = %v, want 7", file.Difficulty.ApproachRate)
+				}
+			},
+		},
+		{
+			name:  "old timing point defaults",
+			input: "osu file format v3\n[TimingPoints]\n1500,400\n",
+			check: func(t *testing.T, file *BeatmapFile) {
+				want := []TimingPoint{{Time: 1500, BeatLength: 400, Meter: 4, Volume: 100, Uninherited: true}}
+				if !reflect.DeepEqual(file.TimingPoints, want) {
+					t.Errorf("TimingPoints = %+v, want %+v", file.TimingPoints, want)
+				}
+				if bpm := file.TimingPoints[0].BPM(); bpm != 150 {
+					t.Errorf("BPM() = %v, want 150", bpm)
+				}
+			},
+		},
+		{
+			name:  "mania hold",
+			input: "osu file format v14\n[HitObjects]\n64,192,500,128,0,1500:0:0:0:0:\n",
+			check: func(t *testing.T, file *BeatmapFile) {
+				want := []HitObject{{X: 64, Y: 192, Time: 500, Type: HitObjectHold, EndTime: 1500, HitSample: "0:0:0:0:"}}
+				if !reflect.DeepEqual(file.HitObjects, want) {
+					t.Errorf("HitObjects = %+v, want %+v", file.HitObjects, want)
+				}
+			},
+		},
+		{
+			name:  "fractional coordinates",
+			input: "osu file format v14\n[HitObjects]\n256.5,192.25,1000.75,1,0\n",
+			check: func(t *testing.T, file *BeatmapFile) {
+				want := []HitObject{{X: 256, Y: 192, Time: 1000, Type: HitObjectCircle}}
+				if !reflect.DeepEqual(file.HitObjects, want) {
+					t.Errorf("HitObjects = %+v, want %+v", file.HitObjects, want)
+				}
+			},
+		},
+		{
+			name:  "combo skip",
+			input: "osu file format v14\n[HitObjects]\n0,0,0,53,0\n",
+			check: func(t *testing.T, file *BeatmapFile) {
+				if skip := file.HitObjects[0].ComboSkip(); skip != 3 {
+					t.Errorf("ComboSkip() = %d, want 3", skip)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := ParseBeatmapFile(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseBeatmapFile() error = %v", err)
+			}
+			tt.check(t, file)
+		})
+	}
+}
+
+func TestParseBeatmapFileInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"format version", "osu file format vX\n"},
+		{"general value", "[General]\nAudioLeadIn: soon\n"},
+		{"hit object fields", "[HitObjects]\n256,192,1000\n"},
+		{"slider without params", "[HitObjects]\n256,192,1000,2,0\n"},
+		{"curve point", "[HitObjects]\n256,192,1000,2,0,B|200,1,100\n"},
+		{"break without end", "[Events]\n2,10000\n"},
+		{"colour", "[Colours]\nCombo1 : 255,128\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBeatmapFile(strings.NewReader(tt.input))
+			if !errors.Is(err, ErrInvalidBeatmapFile) {
+				t.Errorf("ParseBeatmapFile() error = %v, want %v", err, ErrInvalidBeatmapFile)
+			}
+		})
+	}
+}
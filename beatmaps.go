@@ -2,7 +2,8 @@ package gosu
 
 import (
 	"encoding/json"
-	"github.com/mitchellh/mapstructure"
+	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"time"
@@ -42,6 +43,26 @@ type Beatmap struct {
 	URL           string     `json:"url"`
 }
 
+// WithMods returns a copy of the beatmap with difficulty settings, BPM and lengths adjusted for the mods.
+func (b Beatmap) WithMods(mods Mod) Beatmap {
+	rate := mods.ClockRate()
+
+	b.AR = float32(mods.ApproachRate(float64(b.AR)))
+	b.Accuracy = float32(mods.OverallDifficulty(float64(b.Accuracy), b.Mode))
+	b.CS = float32(mods.CircleSize(float64(b.CS), b.Mode))
+	b.Drain = float32(mods.DrainRate(float64(b.Drain)))
+
+	if b.BPM != nil {
+		bpm := *b.BPM * float32(rate)
+		b.BPM = &bpm
+	}
+
+	b.TotalLength = int(math.Round(float64(b.TotalLength) / rate))
+	b.HitLength = int(math.Round(float64(b.HitLength) / rate))
+
+	return b
+}
+
 type GetBeatmapsResponse struct {
 	Beatmaps []BeatmapResponse `json:"beatmaps"`
 }
@@ -176,8 +197,12 @@ type BeatmapScoresRequest struct {
 	client  *Client
 	Beatmap int
 	Mode    *Ruleset
+	Limit   *int
 }
 
+// maxBeatmapScoresLimit is the most scores a beatmap leaderboard returns.
+const maxBeatmapScoresLimit = 100
+
 // GetBeatmapScores returns the top scores for a beatmap. Depending on user preferences, this may only show legacy scores.
 func (c *Client) GetBeatmapScores(beatmap int) *BeatmapScoresRequest {
 	return &BeatmapScoresRequest{client: c, Beatmap: beatmap}
@@ -188,9 +213,22 @@ func (r *BeatmapScoresRequest) SetMode(mode Ruleset) *BeatmapScoresRequest {
 	return r
 }
 
+// SetLimit sets the number of scores returned, between 1 and 100. The API returns 50 by default.
+func (r *BeatmapScoresRequest) SetLimit(limit int) *BeatmapScoresRequest {
+	r.Limit = &limit
+	return r
+}
+
 func (r *BeatmapScoresRequest) Build() (*BeatmapScores, error) {
 	req := r.client.httpClient.R().SetResult(&BeatmapScores{})
 
+	if r.Limit != nil {
+		if *r.Limit < 1 || *r.Limit > maxBeatmapScoresLimit {
+			return nil, fmt.Errorf("%d is %w, must be between 1 and %d", *r.Limit, ErrInvalidLimit, maxBeatmapScoresLimit)
+		}
+		req.SetQueryParam("limit", strconv.Itoa(*r.Limit))
+	}
+
 	req.SetPathParams(map[string]string{
 		"beatmap": strconv.Itoa(r.Beatmap),
 	})
@@ -305,22 +343,30 @@ func (r *LookupBeatmapRequest) Build() (*BeatmapResponse, error) {
 	return resp.Result().(*BeatmapResponse), nil
 }
 
-type BaseDifficultyAttributes struct {
-	Attributes struct {
-		MaxCombo          int                `json:"max_combo"`
-		StarRating        float32            `json:"star_rating"`
-		RulesetAttributes map[string]float32 `json:"ruleset_attributes,remain"`
-	} `json:"attributes"`
+// DifficultyAttributes holds the star rating of a beatmap and the attributes of the ruleset it was calculated for.
+// Only the field matching Mode is set.
+type DifficultyAttributes struct {
+	MaxCombo   int     `json:"max_combo"`
+	StarRating float32 `json:"star_rating"`
+	// Mode is the ruleset the attributes were calculated for, either the requested one or the beatmap's own.
+	Mode   *Ruleset                    `json:"-"`
+	Osu    *OsuDifficultyAttributes    `json:"-"`
+	Taiko  *TaikoDifficultyAttributes  `json:"-"`
+	Fruits *FruitsDifficultyAttributes `json:"-"`
+	Mania  *ManiaDifficultyAttributes  `json:"-"`
 }
 
 type OsuDifficultyAttributes struct {
-	AimDifficulty        float32 `json:"aim_difficulty"`
-	SpeedDifficulty      float32 `json:"speed_difficulty"`
-	SpeedNoteCount       float32 `json:"speed_note_count"`
-	FlashlightDifficulty float32 `json:"flashlight_difficulty"`
-	SliderFactor         float32 `json:"slider_factor"`
-	ApproachRate         float32 `json:"approach_rate"`
-	OverallDifficulty    float32 `json:"overall_difficulty"`
+	AimDifficulty             float32 `json:"aim_difficulty"`
+	AimDifficultSliderCount   float32 `json:"aim_difficult_slider_count"`
+	SpeedDifficulty           float32 `json:"speed_difficulty"`
+	SpeedNoteCount            float32 `json:"speed_note_count"`
+	FlashlightDifficulty      float32 `json:"flashlight_difficulty"`
+	SliderFactor              float32 `json:"slider_factor"`
+	AimDifficultStrainCount   float32 `json:"aim_difficult_strain_count"`
+	SpeedDifficultStrainCount float32 `json:"speed_difficult_strain_count"`
+	ApproachRate              float32 `json:"approach_rate"`
+	OverallDifficulty         float32 `json:"overall_difficulty"`
 }
 
 type TaikoDifficultyAttributes struct {
@@ -328,6 +374,7 @@ type TaikoDifficultyAttributes struct {
 	RhythmDifficulty  float32 `json:"rhythm_difficulty"`
 	ColourDifficulty  float32 `json:"colour_difficulty"`
 	PeakDifficulty    float32 `json:"peak_difficulty"`
+	MonoStaminaFactor float32 `json:"mono_stamina_factor"`
 	GreatHitWindow    float32 `json:"great_hit_window"`
 }
 
@@ -343,10 +390,12 @@ type BeatmapAttributesRequest struct {
 	client  *Client
 	Beatmap int
 	Mods    Mod
+	APIMods []APIMod
 	Mode    *Ruleset
 }
 
 // GetBeatmapAttributes returns difficulty attributes of beatmap with specific mode and mods combination.
+// Without a mode, the beatmap is looked up first to decode the attributes of its own ruleset.
 func (c *Client) GetBeatmapAttributes(beatmap int) *BeatmapAttributesRequest {
 	return &BeatmapAttributesRequest{client: c, Beatmap: beatmap}
 }
@@ -361,48 +410,98 @@ func (r *BeatmapAttributesRequest) AddMods(mods Mod) *BeatmapAttributesRequest {
 	return r
 }
 
+// SetAPIMods sends lazer mods with their settings instead of the legacy bitmask set by SetMods.
+func (r *BeatmapAttributesRequest) SetAPIMods(mods []APIMod) *BeatmapAttributesRequest {
+	r.APIMods = mods
+	return r
+}
+
 func (r *BeatmapAttributesRequest) SetMode(mode Ruleset) *BeatmapAttributesRequest {
 	r.Mode = &mode
 	return r
 }
 
+// BaseDifficultyAttributes is the response GetBeatmapAttributes used to return.
+//
+// Deprecated: GetBeatmapAttributes returns DifficultyAttributes, which decodes the ruleset attributes into typed fields.
+type BaseDifficultyAttributes struct {
+	Attributes struct {
+		MaxCombo          int                `json:"max_combo"`
+		StarRating        float32            `json:"star_rating"`
+		RulesetAttributes map[string]float32 `json:"ruleset_attributes,remain"`
+	} `json:"attributes"`
+}
+
 type GenericDifficultyAttributes struct {
 	Attributes json.RawMessage `json:"attributes"`
 }
 
-func (r *BeatmapAttributesRequest) Build() (*BaseDifficultyAttributes, error) {
-	req := r.client.httpClient.R().SetResult(&map[string]interface{}{}).SetPathParam("beatmap", strconv.Itoa(r.Beatmap))
+func (r *BeatmapAttributesRequest) Build() (*DifficultyAttributes, error) {
+	req := r.client.httpClient.R().SetResult(&GenericDifficultyAttributes{}).SetPathParam("beatmap", strconv.Itoa(r.Beatmap))
+
+	// The attributes are decoded by ruleset, so without a requested one use the beatmap's own.
+	mode := r.Mode
+	if mode == nil {
+		beatmap, err := r.client.GetBeatmap(r.Beatmap).Build()
+		if err != nil {
+			return nil, fmt.Errorf("fetching the ruleset of beatmap %d: %w", r.Beatmap, err)
+		}
+		mode = &beatmap.Mode
+	}
+
+	// Lazer mods are sent instead of the bitmask, so validate the legacy mods among them.
+	mods := r.Mods
+	if r.APIMods != nil {
+		mods, _ = ModsFromAPIMods(r.APIMods)
+	}
+
+	if err := mods.Validate(*mode); err != nil {
+		return nil, err
+	}
 
 	body := make(map[string]interface{})
 
-	if reflect.ValueOf(r.Mods).IsValid() {
+	if r.APIMods != nil {
+		body["mods"] = r.APIMods
+	} else if reflect.ValueOf(r.Mods).IsValid() {
 		body["mods"] = r.Mods
 	}
 
-	if r.Mode != nil {
-		body["ruleset"] = r.Mode
-	}
+	body["ruleset"] = mode
 
 	resp, err := req.SetBody(body).Post("/beatmaps/{beatmap}/attributes")
 	if err != nil {
 		return nil, err
 	}
 
-	attributes := resp.Result().(*map[string]interface{})
+	raw := resp.Result().(*GenericDifficultyAttributes).Attributes
 
-	var result BaseDifficultyAttributes
-	config := &mapstructure.DecoderConfig{
-		Result:  &result,
-		TagName: "json",
+	var result DifficultyAttributes
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
 	}
 
-	decoder, err := mapstructure.NewDecoder(config)
-	if err != nil {
-		return nil, err
+	result.Mode = mode
+
+	var target interface{}
+	switch *result.Mode {
+	case RulesetOsu:
+		result.Osu = &OsuDifficultyAttributes{}
+		target = result.Osu
+	case RulesetTaiko:
+		result.Taiko = &TaikoDifficultyAttributes{}
+		target = result.Taiko
+	case RulesetFruits:
+		result.Fruits = &FruitsDifficultyAttributes{}
+		target = result.Fruits
+	case RulesetMania:
+		result.Mania = &ManiaDifficultyAttributes{}
+		target = result.Mania
+	default:
+		return nil, fmt.Errorf("%d is %w", *result.Mode, ErrInvalidRuleset)
 	}
 
-	err = decoder.Decode(*attributes)
-	if err != nil {
+	if err := json.Unmarshal(raw, target); err != nil {
 		return nil, err
 	}
 
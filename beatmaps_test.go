@@ -0,0 +1,69 @@
+package gosu
+
+import "testing"
+
+func TestBeatmapWithMods(t *testing.T) {
+	bpm := float32(180)
+	beatmap := Beatmap{
+		BeatmapCompact: BeatmapCompact{Mode: RulesetOsu, TotalLength: 150},
+		AR:             9,
+		Accuracy:       8,
+		CS:             4,
+		Drain:          5,
+		BPM:            &bpm,
+		HitLength:      120,
+	}
+
+	tests := []struct {
+		name        string
+		mode        Ruleset
+		mods        Mod
+		ar, od      float64
+		cs, hp, bpm float64
+		total, hit  int
+	}{
+		{"no mods", RulesetOsu, 0, 9, 8, 4, 5, 180, 150, 120},
+		{"hard rock", RulesetOsu, HR, 10, 10, 5.2, 7, 180, 150, 120},
+		{"double time", RulesetOsu, DT, 31.0 / 3, 88.0 / 9, 4, 5, 270, 100, 80},
+		{"nightcore", RulesetOsu, NC, 31.0 / 3, 88.0 / 9, 4, 5, 270, 100, 80},
+		{"easy half time", RulesetOsu, EZ | HT, 1, 8.0 / 9, 2, 2.5, 135, 200, 160},
+		{"taiko double time", RulesetTaiko, DT, 31.0 / 3, 98.0 / 9, 4, 5, 270, 100, 80},
+		{"mania double time", RulesetMania, DT | HR, 11, 10, 4, 7, 270, 100, 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := beatmap
+			b.Mode = tt.mode
+			got := b.WithMods(tt.mods)
+
+			for _, value := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"AR", float64(got.AR), tt.ar},
+				{"Accuracy", float64(got.Accuracy), tt.od},
+				{"CS", float64(got.CS), tt.cs},
+				{"Drain", float64(got.Drain), tt.hp},
+				{"BPM", float64(*got.BPM), tt.bpm},
+			} {
+				if !withinTolerance32(value.got, value.want) {
+					t.Errorf("%s = %v, want %v", value.name, value.got, value.want)
+				}
+			}
+
+			if got.TotalLength != tt.total || got.HitLength != tt.hit {
+				t.Errorf("TotalLength, HitLength = %d, %d, want %d, %d", got.TotalLength, got.HitLength, tt.total, tt.hit)
+			}
+		})
+	}
+
+	if *beatmap.BPM != 180 {
+		t.Errorf("WithMods() changed the BPM of the original beatmap to %v", *beatmap.BPM)
+	}
+}
+
+// withinTolerance32 compares values that went through a float32 field.
+func withinTolerance32(got, want float64) bool {
+	return float64(float32(want)) == got || withinTolerance(got, want)
+}
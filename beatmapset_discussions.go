@@ -1,6 +1,9 @@
 package gosu
 
 import (
+	"errors"
+	"fmt"
+	"github.com/go-resty/resty/v2"
 	"strconv"
 	"time"
 )
@@ -61,16 +64,16 @@ type BeatmapsetDiscussion struct {
 }
 
 type DiscussionPost struct {
-	BeatmapsetDiscussionID int         `json:"beatmapset_discussion_id"`
-	CreatedAt              time.Time   `json:"created_at"`
-	DeletedAt              *time.Time  `json:"deleted_at"`
-	DeletedByID            *int        `json:"deleted_by_id"`
-	ID                     int         `json:"id"`
-	LastEditorID           *int        `json:"last_editor_id"`
-	Message                interface{} `json:"message"`
-	System                 bool        `json:"system"`
-	UpdatedAt              time.Time   `json:"updated_at"`
-	UserID                 int         `json:"user_id"`
+	BeatmapsetDiscussionID int               `json:"beatmapset_discussion_id"`
+	CreatedAt              time.Time         `json:"created_at"`
+	DeletedAt              *time.Time        `json:"deleted_at"`
+	DeletedByID            *int              `json:"deleted_by_id"`
+	ID                     int               `json:"id"`
+	LastEditorID           *int              `json:"last_editor_id"`
+	Message                DiscussionMessage `json:"message"`
+	System                 bool              `json:"system"`
+	UpdatedAt              time.Time         `json:"updated_at"`
+	UserID                 int               `json:"user_id"`
 }
 
 type DiscussionVote struct {
@@ -84,39 +87,78 @@ type DiscussionVote struct {
 
 type DiscussionPostsResponse struct {
 	Beatmapsets  []BeatmapsetCompact    `json:"beatmapsets"`
-	CursorString Cursor                 `json:"cursor_string"`
+	CursorString *string                `json:"cursor_string"`
 	Discussions  []BeatmapsetDiscussion `json:"discussions"`
 	Posts        []DiscussionPost       `json:"posts"`
 	Users        []UserCompact          `json:"users"`
 }
 
 type DiscussionVotesResponse struct {
-	CursorString Cursor                 `json:"cursor_string"`
+	CursorString *string                `json:"cursor_string"`
 	Discussions  []BeatmapsetDiscussion `json:"discussions"`
 	Users        []UserCompact          `json:"users"`
 	Votes        []DiscussionVote       `json:"votes"`
 }
 
+type DiscussionBeatmap struct {
+	Beatmap
+	Checksum *string `json:"checksum"`
+}
+
 type DiscussionsResponse struct {
-	CursorString        Cursor                 `json:"cursor_string"`
+	CursorString        *string                `json:"cursor_string"`
 	Users               []UserCompact          `json:"users"`
 	Discussions         []BeatmapsetDiscussion `json:"discussions"`
 	IncludedDiscussions []BeatmapsetDiscussion `json:"included_discussions"`
 	Beatmapsets         []Beatmapset           `json:"beatmapsets"`
-	Beatmaps            []struct {
-		Beatmap
-		Checksum *string `json:"checksum"`
-	} `json:"beatmaps"`
-	ReviewsConfig struct {
+	Beatmaps            []DiscussionBeatmap    `json:"beatmaps"`
+	ReviewsConfig       struct {
 		MaxBlocks int `json:"max_blocks"`
 	} `json:"reviews_config"`
 }
 
+// DiscussionBaseRequest holds the pagination of discussion listings.
+// Page and CursorString are mutually exclusive; their setters clear each other.
 type DiscussionBaseRequest struct {
 	client *Client
-	Limit  *int
-	Page   *int
-	Sort   *Sort
+	// Limit is the number of results per page, between 5 and 50.
+	Limit        *int
+	Page         *int
+	Sort         *Sort
+	CursorString *string
+}
+
+const (
+	minDiscussionLimit = 5
+	maxDiscussionLimit = 50
+)
+
+// setPagination validates the limit, page and cursor of a discussion listing and adds them to req.
+func (r *DiscussionBaseRequest) setPagination(req *resty.Request) error {
+	if r.Limit != nil {
+		if *r.Limit < minDiscussionLimit || *r.Limit > maxDiscussionLimit {
+			return fmt.Errorf("%d is %w, must be between %d and %d", *r.Limit, ErrInvalidLimit, minDiscussionLimit, maxDiscussionLimit)
+		}
+		req.SetQueryParam("limit", strconv.Itoa(*r.Limit))
+	}
+
+	if r.Page != nil && r.CursorString != nil {
+		return errors.New("page and cursor string cannot both be set")
+	}
+
+	if r.Page != nil {
+		req.SetQueryParam("page", strconv.Itoa(*r.Page))
+	}
+
+	if r.Sort != nil {
+		req.SetQueryParam("sort", string(*r.Sort))
+	}
+
+	if r.CursorString != nil {
+		req.SetQueryParam("cursor_string", *r.CursorString)
+	}
+
+	return nil
 }
 
 type DiscussionPostsRequest struct {
@@ -153,13 +195,16 @@ func (r *DiscussionPostsRequest) SetUser(userID int) *DiscussionPostsRequest {
 	return r
 }
 
+// SetLimit sets the number of results per page, between 5 and 50.
 func (r *DiscussionPostsRequest) SetLimit(limit int) *DiscussionPostsRequest {
 	r.Limit = &limit
 	return r
 }
 
+// SetPage sets the page to fetch, replacing any cursor string.
 func (r *DiscussionPostsRequest) SetPage(page int) *DiscussionPostsRequest {
 	r.Page = &page
+	r.CursorString = nil
 	return r
 }
 
@@ -168,6 +213,14 @@ func (r *DiscussionPostsRequest) SetSort(sort Sort) *DiscussionPostsRequest {
 	return r
 }
 
+// SetCursorString sets the cursor of the page to fetch, as returned in the CursorString of a previous response.
+// It replaces any page number.
+func (r *DiscussionPostsRequest) SetCursorString(cursorString string) *DiscussionPostsRequest {
+	r.CursorString = &cursorString
+	r.Page = nil
+	return r
+}
+
 func (r *DiscussionPostsRequest) Build() (*DiscussionPostsResponse, error) {
 	req := r.client.httpClient.R().SetResult(&DiscussionPostsResponse{})
 
@@ -175,16 +228,8 @@ func (r *DiscussionPostsRequest) Build() (*DiscussionPostsResponse, error) {
 		req.SetQueryParam("beatmapset_discussion_id", strconv.Itoa(*r.BeatmapsetDiscussionID))
 	}
 
-	if r.Limit != nil {
-		req.SetQueryParam("limit", strconv.Itoa(*r.Limit))
-	}
-
-	if r.Page != nil {
-		req.SetQueryParam("page", strconv.Itoa(*r.Page))
-	}
-
-	if r.Sort != nil {
-		req.SetQueryParam("sort", string(*r.Sort))
+	if err := r.setPagination(req); err != nil {
+		return nil, err
 	}
 
 	if r.User != nil {
@@ -240,13 +285,16 @@ func (r *DiscussionVotesRequest) SetUser(userID int) *DiscussionVotesRequest {
 	return r
 }
 
+// SetLimit sets the number of results per page, between 5 and 50.
 func (r *DiscussionVotesRequest) SetLimit(limit int) *DiscussionVotesRequest {
 	r.Limit = &limit
 	return r
 }
 
+// SetPage sets the page to fetch, replacing any cursor string.
 func (r *DiscussionVotesRequest) SetPage(page int) *DiscussionVotesRequest {
 	r.Page = &page
+	r.CursorString = nil
 	return r
 }
 
@@ -255,6 +303,14 @@ func (r *DiscussionVotesRequest) SetSort(sort Sort) *DiscussionVotesRequest {
 	return r
 }
 
+// SetCursorString sets the cursor of the page to fetch, as returned in the CursorString of a previous response.
+// It replaces any page number.
+func (r *DiscussionVotesRequest) SetCursorString(cursorString string) *DiscussionVotesRequest {
+	r.CursorString = &cursorString
+	r.Page = nil
+	return r
+}
+
 func (r *DiscussionVotesRequest) Build() (*DiscussionVotesResponse, error) {
 	req := r.client.httpClient.R().SetResult(&DiscussionVotesResponse{})
 
@@ -274,16 +330,8 @@ func (r *DiscussionVotesRequest) Build() (*DiscussionVotesResponse, error) {
 		req.SetQueryParam("user", strconv.Itoa(*r.User))
 	}
 
-	if r.Limit != nil {
-		req.SetQueryParam("limit", strconv.Itoa(*r.Limit))
-	}
-
-	if r.Page != nil {
-		req.SetQueryParam("page", strconv.Itoa(*r.Page))
-	}
-
-	if r.Sort != nil {
-		req.SetQueryParam("sort", string(*r.Sort))
+	if err := r.setPagination(req); err != nil {
+		return nil, err
 	}
 
 	resp, err := req.Get("beatmapsets/discussions/votes")
@@ -346,13 +394,16 @@ func (r *DiscussionsRequest) SetUser(userID int) *DiscussionsRequest {
 	return r
 }
 
+// SetLimit sets the number of results per page, between 5 and 50.
 func (r *DiscussionsRequest) SetLimit(limit int) *DiscussionsRequest {
 	r.Limit = &limit
 	return r
 }
 
+// SetPage sets the page to fetch, replacing any cursor string.
 func (r *DiscussionsRequest) SetPage(page int) *DiscussionsRequest {
 	r.Page = &page
+	r.CursorString = nil
 	return r
 }
 
@@ -361,6 +412,14 @@ func (r *DiscussionsRequest) SetSort(sort Sort) *DiscussionsRequest {
 	return r
 }
 
+// SetCursorString sets the cursor of the page to fetch, as returned in the CursorString of a previous response.
+// It replaces any page number.
+func (r *DiscussionsRequest) SetCursorString(cursorString string) *DiscussionsRequest {
+	r.CursorString = &cursorString
+	r.Page = nil
+	return r
+}
+
 func (r *DiscussionsRequest) Build() (*DiscussionsResponse, error) {
 	req := r.client.httpClient.R().SetResult(&DiscussionsResponse{})
 
@@ -376,16 +435,8 @@ func (r *DiscussionsRequest) Build() (*DiscussionsResponse, error) {
 		req.SetQueryParam("beatmapset_status", string(*r.BeatmapsetStatus))
 	}
 
-	if r.Limit != nil {
-		req.SetQueryParam("limit", strconv.Itoa(*r.Limit))
-	}
-
-	if r.Page != nil {
-		req.SetQueryParam("page", strconv.Itoa(*r.Page))
-	}
-
-	if r.Sort != nil {
-		req.SetQueryParam("sort", string(*r.Sort))
+	if err := r.setPagination(req); err != nil {
+		return nil, err
 	}
 
 	if r.User != nil {
@@ -409,3 +460,325 @@ func (r *DiscussionsRequest) Build() (*DiscussionsResponse, error) {
 
 	return resp.Result().(*DiscussionsResponse), nil
 }
+
+// discussionWriteResponse is returned by discussion write endpoints, which respond with the beatmapset's discussions
+// either directly or wrapped together with the IDs of the created discussion and posts.
+type discussionWriteResponse struct {
+	BeatmapDiscussionID *int                   `json:"beatmap_discussion_id"`
+	Discussions         []BeatmapsetDiscussion `json:"discussions"`
+	Beatmapset          *struct {
+		Discussions []BeatmapsetDiscussion `json:"discussions"`
+	} `json:"beatmapset"`
+}
+
+func (r *discussionWriteResponse) discussion(id int) (*BeatmapsetDiscussion, error) {
+	discussions := r.Discussions
+	if r.Beatmapset != nil {
+		discussions = r.Beatmapset.Discussions
+	}
+
+	for i := range discussions {
+		if discussions[i].ID == id {
+			return &discussions[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("discussion %d missing from response", id)
+}
+
+type CreateDiscussionRequest struct {
+	client       *Client
+	BeatmapsetID int
+	MessageType  MessageType
+	Message      string
+	BeatmapID    *int
+	Timestamp    *int
+}
+
+// CreateDiscussion starts a discussion on a beatmapset as the user of a client created with NewClientWithToken.
+func (c *Client) CreateDiscussion(beatmapsetID int, messageType MessageType, message string) *CreateDiscussionRequest {
+	return &CreateDiscussionRequest{client: c, BeatmapsetID: beatmapsetID, MessageType: messageType, Message: message}
+}
+
+// SetBeatmapID attaches the discussion to a difficulty instead of the whole beatmapset.
+func (r *CreateDiscussionRequest) SetBeatmapID(beatmapID int) *CreateDiscussionRequest {
+	r.BeatmapID = &beatmapID
+	return r
+}
+
+// SetTimestamp sets the position in milliseconds the discussion refers to. It requires a beatmap ID.
+func (r *CreateDiscussionRequest) SetTimestamp(timestamp int) *CreateDiscussionRequest {
+	r.Timestamp = &timestamp
+	return r
+}
+
+func (r *CreateDiscussionRequest) Build() (*BeatmapsetDiscussion, error) {
+	if r.Timestamp != nil && r.BeatmapID == nil {
+		return nil, errors.New("a timestamp requires a beatmap ID")
+	}
+
+	discussion := map[string]interface{}{"message_type": r.MessageType}
+
+	if r.BeatmapID != nil {
+		discussion["beatmap_id"] = *r.BeatmapID
+	}
+
+	if r.Timestamp != nil {
+		discussion["timestamp"] = *r.Timestamp
+	}
+
+	body := map[string]interface{}{
+		"beatmapset_id":           r.BeatmapsetID,
+		"beatmap_discussion":      discussion,
+		"beatmap_discussion_post": map[string]string{"message": r.Message},
+	}
+
+	resp, err := r.client.httpClient.R().
+		SetResult(&discussionWriteResponse{}).
+		SetBody(body).
+		Post("beatmapsets/discussions/posts")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	result := resp.Result().(*discussionWriteResponse)
+	if result.BeatmapDiscussionID == nil {
+		return nil, errors.New("discussion ID missing from response")
+	}
+
+	return result.discussion(*result.BeatmapDiscussionID)
+}
+
+type ReplyDiscussionRequest struct {
+	client       *Client
+	DiscussionID int
+	Message      string
+	Resolved     *bool
+}
+
+// ReplyToDiscussion posts a reply to a discussion as the user of a client created with NewClientWithToken.
+func (c *Client) ReplyToDiscussion(discussionID int, message string) *ReplyDiscussionRequest {
+	return &ReplyDiscussionRequest{client: c, DiscussionID: discussionID, Message: message}
+}
+
+// ResolveDiscussion replies to a discussion, marking it as resolved or reopening it.
+func (c *Client) ResolveDiscussion(discussionID int, resolved bool, message string) *ReplyDiscussionRequest {
+	return c.ReplyToDiscussion(discussionID, message).SetResolved(resolved)
+}
+
+// SetResolved marks the discussion as resolved or reopens it along with the reply.
+func (r *ReplyDiscussionRequest) SetResolved(resolved bool) *ReplyDiscussionRequest {
+	r.Resolved = &resolved
+	return r
+}
+
+func (r *ReplyDiscussionRequest) Build() (*BeatmapsetDiscussion, error) {
+	body := map[string]interface{}{
+		"beatmap_discussion_id":   r.DiscussionID,
+		"beatmap_discussion_post": map[string]string{"message": r.Message},
+	}
+
+	if r.Resolved != nil {
+		body["beatmap_discussion"] = map[string]bool{"resolved": *r.Resolved}
+	}
+
+	resp, err := r.client.httpClient.R().
+		SetResult(&discussionWriteResponse{}).
+		SetBody(body).
+		Post("beatmapsets/discussions/posts")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Result().(*discussionWriteResponse).discussion(r.DiscussionID)
+}
+
+type VoteDiscussionRequest struct {
+	client       *Client
+	DiscussionID int
+	Direction    DiscussionVoteDirection
+}
+
+// VoteDiscussion votes on a discussion as the user of a client created with NewClientWithToken.
+func (c *Client) VoteDiscussion(discussionID int, direction DiscussionVoteDirection) *VoteDiscussionRequest {
+	return &VoteDiscussionRequest{client: c, DiscussionID: discussionID, Direction: direction}
+}
+
+func (r *VoteDiscussionRequest) Build() (*BeatmapsetDiscussion, error) {
+	score, err := strconv.Atoi(string(r.Direction))
+	if err != nil {
+		return nil, fmt.Errorf("invalid vote direction %q", r.Direction)
+	}
+
+	resp, err := r.client.httpClient.R().
+		SetResult(&discussionWriteResponse{}).
+		SetPathParam("discussion", strconv.Itoa(r.DiscussionID)).
+		SetBody(map[string]interface{}{
+			"beatmap_discussion_vote": map[string]int{"score": score},
+		}).
+		Put("beatmapsets/discussions/{discussion}/vote")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Result().(*discussionWriteResponse).discussion(r.DiscussionID)
+}
+
+type DiscussionKudosuRequest struct {
+	client       *Client
+	DiscussionID int
+	Allow        bool
+}
+
+// AllowDiscussionKudosu allows kudosu to be given for a discussion. It requires a client created with
+// NewClientWithToken for a user with moderation permissions.
+func (c *Client) AllowDiscussionKudosu(discussionID int) *DiscussionKudosuRequest {
+	return &DiscussionKudosuRequest{client: c, DiscussionID: discussionID, Allow: true}
+}
+
+// DenyDiscussionKudosu prevents kudosu from being given for a discussion. It requires a client created with
+// NewClientWithToken for a user with moderation permissions.
+func (c *Client) DenyDiscussionKudosu(discussionID int) *DiscussionKudosuRequest {
+	return &DiscussionKudosuRequest{client: c, DiscussionID: discussionID, Allow: false}
+}
+
+func (r *DiscussionKudosuRequest) Build() (*BeatmapsetDiscussion, error) {
+	url := "beatmapsets/discussions/{discussion}/deny-kudosu"
+	if r.Allow {
+		url = "beatmapsets/discussions/{discussion}/allow-kudosu"
+	}
+
+	resp, err := r.client.httpClient.R().
+		SetResult(&discussionWriteResponse{}).
+		SetPathParam("discussion", strconv.Itoa(r.DiscussionID)).
+		Post(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Result().(*discussionWriteResponse).discussion(r.DiscussionID)
+}
+
+// ErrMaxPagesReached is returned by BuildAll alongside the pages it fetched when more pages remain after maxPages.
+// The CursorString of the response continues after the last page fetched.
+var ErrMaxPagesReached = errors.New("max pages reached")
+
+// fetchAllDiscussionPages builds a request repeatedly, following the cursor of each response until the last page
+// or maxPages pages, and merges the pages into the first response.
+// If the cap stops it before the last page, it returns the merged response with ErrMaxPagesReached.
+func fetchAllDiscussionPages[T any](base *DiscussionBaseRequest, maxPages int, build func() (*T, error), cursor func(*T) *string, merge func(all, page *T)) (*T, error) {
+	if maxPages <= 0 {
+		return nil, fmt.Errorf("max pages must be positive, got %d", maxPages)
+	}
+
+	savedCursor, savedPage := base.CursorString, base.Page
+	defer func() {
+		base.CursorString, base.Page = savedCursor, savedPage
+	}()
+
+	var all *T
+	seen := make(map[string]bool)
+
+	for pages := 1; ; pages++ {
+		page, err := build()
+		if err != nil {
+			return nil, err
+		}
+
+		if all == nil {
+			all = page
+		} else {
+			merge(all, page)
+		}
+
+		next := cursor(page)
+		if next == nil || seen[*next] {
+			return all, nil
+		}
+		if pages >= maxPages {
+			return all, ErrMaxPagesReached
+		}
+		seen[*next] = true
+
+		base.CursorString = next
+		base.Page = nil
+	}
+}
+
+// appendUnique appends the items of src whose ID is not already in dst.
+func appendUnique[T any](dst, src []T, id func(T) int) []T {
+	ids := make(map[int]bool, len(dst))
+	for _, item := range dst {
+		ids[id(item)] = true
+	}
+
+	for _, item := range src {
+		if !ids[id(item)] {
+			ids[id(item)] = true
+			dst = append(dst, item)
+		}
+	}
+
+	return dst
+}
+
+// BuildAll fetches up to maxPages pages of discussions by following the cursor string, merging them into one response.
+// If more pages remain, it returns the pages fetched with ErrMaxPagesReached, and the CursorString of the response
+// continues after the last page fetched.
+func (r *DiscussionsRequest) BuildAll(maxPages int) (*DiscussionsResponse, error) {
+	return fetchAllDiscussionPages(&r.DiscussionBaseRequest, maxPages, r.Build,
+		func(resp *DiscussionsResponse) *string { return resp.CursorString },
+		func(all, page *DiscussionsResponse) {
+			all.CursorString = page.CursorString
+			all.Discussions = append(all.Discussions, page.Discussions...)
+			all.IncludedDiscussions = appendUnique(all.IncludedDiscussions, page.IncludedDiscussions, func(d BeatmapsetDiscussion) int { return d.ID })
+			all.Users = appendUnique(all.Users, page.Users, func(u UserCompact) int { return u.ID })
+			all.Beatmapsets = appendUnique(all.Beatmapsets, page.Beatmapsets, func(b Beatmapset) int { return b.ID })
+			all.Beatmaps = appendUnique(all.Beatmaps, page.Beatmaps, func(b DiscussionBeatmap) int { return b.ID })
+		})
+}
+
+// BuildAll fetches up to maxPages pages of posts by following the cursor string, merging them into one response.
+// If more pages remain, it returns the pages fetched with ErrMaxPagesReached, and the CursorString of the response
+// continues after the last page fetched.
+func (r *DiscussionPostsRequest) BuildAll(maxPages int) (*DiscussionPostsResponse, error) {
+	return fetchAllDiscussionPages(&r.DiscussionBaseRequest, maxPages, r.Build,
+		func(resp *DiscussionPostsResponse) *string { return resp.CursorString },
+		func(all, page *DiscussionPostsResponse) {
+			all.CursorString = page.CursorString
+			all.Posts = append(all.Posts, page.Posts...)
+			all.Discussions = appendUnique(all.Discussions, page.Discussions, func(d BeatmapsetDiscussion) int { return d.ID })
+			all.Users = appendUnique(all.Users, page.Users, func(u UserCompact) int { return u.ID })
+			all.Beatmapsets = appendUnique(all.Beatmapsets, page.Beatmapsets, func(b BeatmapsetCompact) int { return b.ID })
+		})
+}
+
+// BuildAll fetches up to maxPages pages of votes by following the cursor string, merging them into one response.
+// If more pages remain, it returns the pages fetched with ErrMaxPagesReached, and the CursorString of the response
+// continues after the last page fetched.
+func (r *DiscussionVotesRequest) BuildAll(maxPages int) (*DiscussionVotesResponse, error) {
+	return fetchAllDiscussionPages(&r.DiscussionBaseRequest, maxPages, r.Build,
+		func(resp *DiscussionVotesResponse) *string { return resp.CursorString },
+		func(all, page *DiscussionVotesResponse) {
+			all.CursorString = page.CursorString
+			all.Votes = append(all.Votes, page.Votes...)
+			all.Discussions = appendUnique(all.Discussions, page.Discussions, func(d BeatmapsetDiscussion) int { return d.ID })
+			all.Users = appendUnique(all.Users, page.Users, func(u UserCompact) int { return u.ID })
+		})
+}
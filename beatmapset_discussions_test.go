@@ -0,0 +1,149 @@
+package gosu
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestDiscussionRequestsQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(client *Client) error
+		want  url.Values
+	}{
+		{
+			name: "discussions",
+			build: func(client *Client) error {
+				_, err := client.GetDiscussions().
+					SetBeatmapsetID(45).
+					SetBeatmapsetStatus(DiscussionStatusQualified).
+					AddMessageType(MessageTypeProblem).
+					AddMessageType(MessageTypeSuggestion).
+					SetOnlyUnresolved(true).
+					SetLimit(50).
+					SetCursorString("abc").
+					Build()
+				return err
+			},
+			want: url.Values{
+				"beatmapset_id":     {"45"},
+				"beatmapset_status": {"qualified"},
+				"message_types[]":   {"problem", "suggestion"},
+				"only_unresolved":   {"true"},
+				"limit":             {"50"},
+				"cursor_string":     {"abc"},
+			},
+		},
+		{
+			name: "posts",
+			build: func(client *Client) error {
+				_, err := client.GetDiscussionPosts().
+					SetDiscussionID(7).
+					AddType(DiscussionPostTypeFirst).
+					AddType(DiscussionPostTypeReply).
+					SetUser(2).
+					SetPage(3).
+					SetSort(SortDescending).
+					Build()
+				return err
+			},
+			want: url.Values{
+				"beatmapset_discussion_id": {"7"},
+				"types[]":                  {"first", "reply"},
+				"user":                     {"2"},
+				"page":                     {"3"},
+				"sort":                     {"id_desc"},
+			},
+		},
+		{
+			name: "votes",
+			build: func(client *Client) error {
+				_, err := client.GetDiscussionVotes().
+					SetDiscussionID(7).
+					SetReceiver(3).
+					SetScore(DiscussionVoteDirectionDown).
+					SetLimit(5).
+					Build()
+				return err
+			},
+			want: url.Values{
+				"beatmapset_discussion_id": {"7"},
+				"receiver":                 {"3"},
+				"score":                    {"-1"},
+				"limit":                    {"5"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, query := newQueryRecordingClient(t, `{}`)
+
+			if err := tt.build(client); err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if !reflect.DeepEqual(*query, tt.want) {
+				t.Errorf("query = %v, want %v", *query, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscussionVotesBuildAll(t *testing.T) {
+	// Each page holds one vote and the cursor of the next page, until the third and last page.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		page := 1
+		if cursor := req.URL.Query().Get("cursor_string"); cursor != "" {
+			fmt.Sscan(cursor, &page)
+		}
+
+		next := "null"
+		if page < 3 {
+			next = fmt.Sprintf(`"%d"`, page+1)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"votes": [{"id": %d}], "cursor_string": %s}`, page, next)
+	}))
+	t.Cleanup(server.Close)
+
+	client := newClient(server.Client())
+	client.httpClient.SetBaseURL(server.URL)
+
+	tests := []struct {
+		maxPages   int
+		wantVotes  int
+		wantCursor *string
+		err        error
+	}{
+		{1, 1, ptr("2"), ErrMaxPagesReached},
+		{2, 2, ptr("3"), ErrMaxPagesReached},
+		{3, 3, nil, nil},
+		{10, 3, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.maxPages), func(t *testing.T) {
+			request := client.GetDiscussionVotes()
+
+			got, err := request.BuildAll(tt.maxPages)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("BuildAll(%d) error = %v, want %v", tt.maxPages, err, tt.err)
+			}
+			if len(got.Votes) != tt.wantVotes {
+				t.Errorf("BuildAll(%d) returned %d votes, want %d", tt.maxPages, len(got.Votes), tt.wantVotes)
+			}
+			if !reflect.DeepEqual(got.CursorString, tt.wantCursor) {
+				t.Errorf("BuildAll(%d) CursorString = %v, want %v", tt.maxPages, got.CursorString, tt.wantCursor)
+			}
+			if request.CursorString != nil {
+				t.Errorf("BuildAll(%d) left the request cursor at %q", tt.maxPages, *request.CursorString)
+			}
+		})
+	}
+}
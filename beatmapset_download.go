@@ -0,0 +1,363 @@
+package gosu
+
+import (
+	"archive/zip"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrRangeNotSatisfied = errors.New("server ignored range request")
+	ErrChecksumMismatch  = errors.New("checksum mismatch")
+)
+
+type BeatmapsetDownloadRequest struct {
+	client     *Client
+	Beatmapset int
+	NoVideo    bool
+	// Offset is the number of bytes already downloaded. The download resumes from it using an HTTP range request.
+	Offset int64
+	// OnProgress is called as the download is written with the number of bytes downloaded so far, including Offset,
+	// and the total size of the file, or -1 if it is unknown.
+	OnProgress func(downloaded, total int64)
+}
+
+// DownloadBeatmapset downloads the .osz archive of a beatmapset.
+func (c *Client) DownloadBeatmapset(beatmapset int) *BeatmapsetDownloadRequest {
+	return &BeatmapsetDownloadRequest{client: c, Beatmapset: beatmapset}
+}
+
+func (r *BeatmapsetDownloadRequest) SetNoVideo(noVideo bool) *BeatmapsetDownloadRequest {
+	r.NoVideo = noVideo
+	return r
+}
+
+func (r *BeatmapsetDownloadRequest) SetOffset(offset int64) *BeatmapsetDownloadRequest {
+	r.Offset = offset
+	return r
+}
+
+func (r *BeatmapsetDownloadRequest) SetOnProgress(onProgress func(downloaded, total int64)) *BeatmapsetDownloadRequest {
+	r.OnProgress = onProgress
+	return r
+}
+
+// download starts the request from Offset. partial reports whether the server honoured the range request,
+// and total is the size of the whole file or -1 if it is unknown.
+func (r *BeatmapsetDownloadRequest) download() (body io.ReadCloser, partial bool, total int64, err error) {
+	return r.downloadFrom(r.Offset)
+}
+
+func (r *BeatmapsetDownloadRequest) downloadFrom(offset int64) (body io.ReadCloser, partial bool, total int64, err error) {
+	req := r.client.httpClient.R().SetDoNotParseResponse(true).SetPathParam("beatmapset", strconv.Itoa(r.Beatmapset))
+
+	if r.NoVideo {
+		req.SetQueryParam("noVideo", "1")
+	}
+
+	if offset > 0 {
+		req.SetHeader("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := req.Get("beatmapsets/{beatmapset}/download")
+	if err != nil {
+		return nil, false, 0, err
+	}
+
+	if resp.IsError() {
+		resp.RawBody().Close()
+		switch resp.StatusCode() {
+		case 404:
+			return nil, false, 0, errors.New("not found")
+		case 416:
+			// The offset is at or past the end of the file. Only an offset equal to its size means the download is
+			// complete, otherwise what was downloaded belongs to another file, so download it again from the start.
+			if size, ok := unsatisfiedRangeSize(resp.Header().Get("Content-Range")); ok && size == offset {
+				return io.NopCloser(strings.NewReader("")), true, size, nil
+			}
+			return r.downloadFrom(0)
+		}
+		return nil, false, 0, fmt.Errorf("unexpected status: %s", resp.Status())
+	}
+
+	partial = resp.StatusCode() == 206
+	total = resp.RawResponse.ContentLength
+	if total >= 0 && partial {
+		total += offset
+	}
+
+	return resp.RawBody(), partial, total, nil
+}
+
+// unsatisfiedRangeSize returns the size of the file from the Content-Range header of a 416 response, "bytes */size".
+func unsatisfiedRangeSize(contentRange string) (int64, bool) {
+	size, ok := strings.CutPrefix(contentRange, "bytes */")
+	if !ok {
+		return 0, false
+	}
+
+	total, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || total < 0 {
+		return 0, false
+	}
+
+	return total, true
+}
+
+// Build returns the .osz archive, or the rest of it when Offset is set. The caller is responsible for closing the returned reader.
+// It returns ErrRangeNotSatisfied if Offset is set and the server sent the whole file, which it also does when
+// Offset is past the end of the file.
+func (r *BeatmapsetDownloadRequest) Build() (io.ReadCloser, error) {
+	body, partial, _, err := r.download()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Offset > 0 && !partial {
+		body.Close()
+		return nil, ErrRangeNotSatisfied
+	}
+
+	return body, nil
+}
+
+// WriteTo streams the .osz archive to w and returns the number of bytes written.
+// It returns ErrRangeNotSatisfied if Offset is set and the server sent the whole file, which it also does when
+// Offset is past the end of the file.
+func (r *BeatmapsetDownloadRequest) WriteTo(w io.Writer) (int64, error) {
+	body, partial, total, err := r.download()
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	if r.Offset > 0 && !partial {
+		return 0, ErrRangeNotSatisfied
+	}
+
+	return r.copy(w, body, r.Offset, total)
+}
+
+// SaveFile downloads the .osz archive to path, resuming from the existing size of the file if it already exists.
+// The file is downloaded from the start if the server does not support resuming, or if it is larger than the archive.
+func (r *BeatmapsetDownloadRequest) SaveFile(path string) (int64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	r.Offset = info.Size()
+
+	body, partial, total, err := r.download()
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	offset := r.Offset
+	if !partial {
+		offset = 0
+		if err := file.Truncate(0); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return r.copy(file, body, offset, total)
+}
+
+func (r *BeatmapsetDownloadRequest) copy(w io.Writer, body io.Reader, offset, total int64) (int64, error) {
+	if r.OnProgress == nil {
+		return io.Copy(w, body)
+	}
+
+	r.OnProgress(offset, total)
+
+	return io.Copy(&progressWriter{w: w, downloaded: offset, total: total, onProgress: r.OnProgress}, body)
+}
+
+type progressWriter struct {
+	w          io.Writer
+	downloaded int64
+	total      int64
+	onProgress func(downloaded, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.downloaded += int64(n)
+	p.onProgress(p.downloaded, p.total)
+	return n, err
+}
+
+// Osz is an opened .osz beatmapset archive.
+type Osz struct {
+	zip *zip.Reader
+	// Beatmaps maps the MD5 checksum of each .osu file in the archive to its name.
+	Beatmaps map[string]string
+}
+
+// ReadOsz opens a .osz archive and indexes the .osu files it contains by MD5 checksum.
+func ReadOsz(r io.ReaderAt, size int64) (*Osz, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	osz := &Osz{zip: reader, Beatmaps: make(map[string]string)}
+
+	for _, file := range reader.File {
+		if !strings.EqualFold(filepath.Ext(file.Name), ".osu") {
+			continue
+		}
+
+		checksum, err := zipFileMD5(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file.Name, err)
+		}
+
+		osz.Beatmaps[checksum] = file.Name
+	}
+
+	return osz, nil
+}
+
+func zipFileMD5(file *zip.File) (string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, rc); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Files returns the names of all files in the archive.
+func (o *Osz) Files() []string {
+	names := make([]string, len(o.zip.File))
+	for i, file := range o.zip.File {
+		names[i] = file.Name
+	}
+	return names
+}
+
+// Open opens a file in the archive by name.
+func (o *Osz) Open(name string) (io.ReadCloser, error) {
+	return o.zip.Open(name)
+}
+
+// Beatmap parses the .osu file with the given MD5 checksum.
+func (o *Osz) Beatmap(checksum string) (*BeatmapFile, error) {
+	name, ok := o.Beatmaps[checksum]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	rc, err := o.zip.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return ParseBeatmapFile(rc)
+}
+
+// Verify checks that the archive contains a .osu file for every checksum, returning ErrChecksumMismatch if any are missing.
+func (o *Osz) Verify(checksums []string) error {
+	var missing []string
+
+	for _, checksum := range checksums {
+		if _, ok := o.Beatmaps[checksum]; !ok {
+			missing = append(missing, checksum)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrChecksumMismatch, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// VerifyBeatmapset checks that the archive contains every beatmap of a beatmapset in its current version.
+func (o *Osz) VerifyBeatmapset(beatmapset *LookupBeatmapsetResponse) error {
+	checksums := make([]string, 0, len(beatmapset.Beatmaps))
+	for _, beatmap := range beatmapset.Beatmaps {
+		checksums = append(checksums, beatmap.Checksum)
+	}
+
+	return o.Verify(checksums)
+}
+
+// Extract unpacks the archive into dir. Files that would be written outside of dir are rejected.
+func (o *Osz) Extract(dir string) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range o.zip.File {
+		path := filepath.Join(root, filepath.FromSlash(file.Name))
+		if path != root && !strings.HasPrefix(path, root+string(filepath.Separator)) {
+			return fmt.Errorf("illegal file path in archive: %s", file.Name)
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := extractZipFile(file, path); err != nil {
+			return fmt.Errorf("extracting %s: %w", file.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func extractZipFile(file *zip.File, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,95 @@
+package gosu
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnsatisfiedRangeSize(t *testing.T) {
+	tests := []struct {
+		header string
+		want   int64
+		ok     bool
+	}{
+		{"bytes */1234", 1234, true},
+		{"bytes */0", 0, true},
+		{"bytes 0-99/1234", 0, false},
+		{"bytes */*", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := unsatisfiedRangeSize(tt.header)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("unsatisfiedRangeSize(%q) = %d, %v, want %d, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBeatmapsetDownloadSaveFile(t *testing.T) {
+	archive := bytes.Repeat([]byte("osz archive "), 100)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasSuffix(req.URL.Path, "/beatmapsets/1/download") {
+			http.NotFound(w, req)
+			return
+		}
+		http.ServeContent(w, req, "1.osz", time.Time{}, bytes.NewReader(archive))
+	}))
+	defer server.Close()
+
+	client := newClient(server.Client())
+	client.httpClient.SetBaseURL(server.URL)
+
+	tests := []struct {
+		name     string
+		existing []byte
+		written  int64
+	}{
+		{"new file", nil, int64(len(archive))},
+		{"partial file", archive[:500], int64(len(archive) - 500)},
+		{"complete file", archive, 0},
+		{"larger file", append(append([]byte(nil), archive...), "stale"...), int64(len(archive))},
+		{"different file", bytes.Repeat([]byte("x"), 2*len(archive)), int64(len(archive))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "1.osz")
+			if tt.existing != nil {
+				if err := os.WriteFile(path, tt.existing, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var downloaded, total int64
+			written, err := client.DownloadBeatmapset(1).SetOnProgress(func(d, t int64) { downloaded, total = d, t }).SaveFile(path)
+			if err != nil {
+				t.Fatalf("SaveFile() error = %v", err)
+			}
+
+			if written != tt.written {
+				t.Errorf("SaveFile() = %d, want %d", written, tt.written)
+			}
+
+			if downloaded != int64(len(archive)) || total != int64(len(archive)) {
+				t.Errorf("OnProgress(%d, %d), want (%d, %d)", downloaded, total, len(archive), len(archive))
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(data, archive) {
+				t.Errorf("file is %d bytes, want the %d byte archive", len(data), len(archive))
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package gosu
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type BeatmapsetEventType string
+
+const (
+	BeatmapsetEventNominate                BeatmapsetEventType = "nominate"
+	BeatmapsetEventLove                    BeatmapsetEventType = "love"
+	BeatmapsetEventRemoveFromLoved         BeatmapsetEventType = "remove_from_loved"
+	BeatmapsetEventQualify                 BeatmapsetEventType = "qualify"
+	BeatmapsetEventDisqualify              BeatmapsetEventType = "disqualify"
+	BeatmapsetEventApprove                 BeatmapsetEventType = "approve"
+	BeatmapsetEventRank                    BeatmapsetEventType = "rank"
+	BeatmapsetEventKudosuAllow             BeatmapsetEventType = "kudosu_allow"
+	BeatmapsetEventKudosuDeny              BeatmapsetEventType = "kudosu_deny"
+	BeatmapsetEventKudosuGain              BeatmapsetEventType = "kudosu_gain"
+	BeatmapsetEventKudosuLost              BeatmapsetEventType = "kudosu_lost"
+	BeatmapsetEventKudosuRecalculate       BeatmapsetEventType = "kudosu_recalculate"
+	BeatmapsetEventIssueResolve            BeatmapsetEventType = "issue_resolve"
+	BeatmapsetEventIssueReopen             BeatmapsetEventType = "issue_reopen"
+	BeatmapsetEventDiscussionLock          BeatmapsetEventType = "discussion_lock"
+	BeatmapsetEventDiscussionUnlock        BeatmapsetEventType = "discussion_unlock"
+	BeatmapsetEventDiscussionDelete        BeatmapsetEventType = "discussion_delete"
+	BeatmapsetEventDiscussionRestore       BeatmapsetEventType = "discussion_restore"
+	BeatmapsetEventDiscussionPostDelete    BeatmapsetEventType = "discussion_post_delete"
+	BeatmapsetEventDiscussionPostRestore   BeatmapsetEventType = "discussion_post_restore"
+	BeatmapsetEventNominationReset         BeatmapsetEventType = "nomination_reset"
+	BeatmapsetEventNominationResetReceived BeatmapsetEventType = "nomination_reset_received"
+	BeatmapsetEventGenreEdit               BeatmapsetEventType = "genre_edit"
+	BeatmapsetEventLanguageEdit            BeatmapsetEventType = "language_edit"
+	BeatmapsetEventNSFWToggle              BeatmapsetEventType = "nsfw_toggle"
+	BeatmapsetEventOffsetEdit              BeatmapsetEventType = "offset_edit"
+	BeatmapsetEventTagsEdit                BeatmapsetEventType = "tags_edit"
+	BeatmapsetEventBeatmapOwnerChange      BeatmapsetEventType = "beatmap_owner_change"
+)
+
+// BeatmapsetEventComment holds the details of a beatmapset event. Which fields are set depends on the event type.
+type BeatmapsetEventComment struct {
+	BeatmapDiscussionID     *int `json:"beatmap_discussion_id"`
+	BeatmapDiscussionPostID *int `json:"beatmap_discussion_post_id"`
+	// Modes are the rulesets nominated for, set on nominate events.
+	Modes []Ruleset `json:"modes"`
+	// NominatorIDs are the users whose nominations were reset, set on disqualify and nomination_reset events.
+	NominatorIDs []int   `json:"nominator_ids"`
+	Reason       *string `json:"reason"`
+	// NewVote and Votes are set on kudosu_gain and kudosu_lost events.
+	NewVote *BeatmapsetEventVote  `json:"new_vote"`
+	Votes   []BeatmapsetEventVote `json:"votes"`
+	// Old and New are the previous and new values on edit and toggle events.
+	// They are a number, string, boolean or an object with an id and name depending on the event type.
+	Old json.RawMessage `json:"old"`
+	New json.RawMessage `json:"new"`
+	// BeatmapID, BeatmapVersion, NewUserID and NewUserUsername are set on beatmap_owner_change events.
+	BeatmapID       *int    `json:"beatmap_id"`
+	BeatmapVersion  *string `json:"beatmap_version"`
+	NewUserID       *int    `json:"new_user_id"`
+	NewUserUsername *string `json:"new_user_username"`
+}
+
+// UnmarshalJSON decodes the comment object. Some old events have a plain text comment, which is decoded into Reason.
+func (c *BeatmapsetEventComment) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = BeatmapsetEventComment{Reason: &text}
+		return nil
+	}
+
+	type comment BeatmapsetEventComment
+	return json.Unmarshal(data, (*comment)(c))
+}
+
+type BeatmapsetEventVote struct {
+	UserID int `json:"user_id"`
+	Score  int `json:"score"`
+}
+
+type BeatmapsetEvent struct {
+	ID         int                     `json:"id"`
+	Type       BeatmapsetEventType     `json:"type"`
+	Comment    *BeatmapsetEventComment `json:"comment"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UserID     *int                    `json:"user_id"`
+	Beatmapset *BeatmapsetCompact      `json:"beatmapset"`
+	Discussion *BeatmapsetDiscussion   `json:"discussion"`
+}
+
+type BeatmapsetEventsResponse struct {
+	Events []BeatmapsetEvent `json:"events"`
+	Users  []UserCompact     `json:"users"`
+}
+
+// BeatmapsetEventsRequest is paginated by page only, since the endpoint returns no cursor.
+type BeatmapsetEventsRequest struct {
+	client *Client
+	// Limit is the number of results per page, between 5 and 50.
+	Limit        *int
+	Page         *int
+	Sort         *Sort
+	User         *int
+	Types        []BeatmapsetEventType
+	BeatmapsetID *int
+	MinDate      *time.Time
+	MaxDate      *time.Time
+}
+
+// GetBeatmapsetEvents returns the modding history of beatmapsets, such as nominations, disqualifications and kudosu changes.
+func (c *Client) GetBeatmapsetEvents() *BeatmapsetEventsRequest {
+	return &BeatmapsetEventsRequest{client: c}
+}
+
+func (r *BeatmapsetEventsRequest) SetUser(userID int) *BeatmapsetEventsRequest {
+	r.User = &userID
+	return r
+}
+
+func (r *BeatmapsetEventsRequest) AddType(eventType BeatmapsetEventType) *BeatmapsetEventsRequest {
+	r.Types = append(r.Types, eventType)
+	return r
+}
+
+func (r *BeatmapsetEventsRequest) SetTypes(types []BeatmapsetEventType) *BeatmapsetEventsRequest {
+	r.Types = types
+	return r
+}
+
+func (r *BeatmapsetEventsRequest) SetBeatmapsetID(beatmapsetID int) *BeatmapsetEventsRequest {
+	r.BeatmapsetID = &beatmapsetID
+	return r
+}
+
+func (r *BeatmapsetEventsRequest) SetMinDate(minDate time.Time) *BeatmapsetEventsRequest {
+	r.MinDate = &minDate
+	return r
+}
+
+func (r *BeatmapsetEventsRequest) SetMaxDate(maxDate time.Time) *BeatmapsetEventsRequest {
+	r.MaxDate = &maxDate
+	return r
+}
+
+// SetLimit sets the number of results per page, between 5 and 50.
+func (r *BeatmapsetEventsRequest) SetLimit(limit int) *BeatmapsetEventsRequest {
+	r.Limit = &limit
+	return r
+}
+
+func (r *BeatmapsetEventsRequest) SetPage(page int) *BeatmapsetEventsRequest {
+	r.Page = &page
+	return r
+}
+
+func (r *BeatmapsetEventsRequest) SetSort(sort Sort) *BeatmapsetEventsRequest {
+	r.Sort = &sort
+	return r
+}
+
+func (r *BeatmapsetEventsRequest) Build() (*BeatmapsetEventsResponse, error) {
+	if r.Limit != nil && (*r.Limit < minDiscussionLimit || *r.Limit > maxDiscussionLimit) {
+		return nil, fmt.Errorf("%d is %w, must be between %d and %d", *r.Limit, ErrInvalidLimit, minDiscussionLimit, maxDiscussionLimit)
+	}
+
+	req := r.client.httpClient.R().SetResult(&BeatmapsetEventsResponse{})
+
+	if r.User != nil {
+		req.SetQueryParam("user", strconv.Itoa(*r.User))
+	}
+
+	for _, eventType := range r.Types {
+		req.QueryParam.Add("types[]", string(eventType))
+	}
+
+	if r.BeatmapsetID != nil {
+		req.SetQueryParam("beatmapset_id", strconv.Itoa(*r.BeatmapsetID))
+	}
+
+	if r.MinDate != nil {
+		req.SetQueryParam("min_date", r.MinDate.Format("2006-01-02"))
+	}
+
+	if r.MaxDate != nil {
+		req.SetQueryParam("max_date", r.MaxDate.Format("2006-01-02"))
+	}
+
+	if r.Limit != nil {
+		req.SetQueryParam("limit", strconv.Itoa(*r.Limit))
+	}
+
+	if r.Page != nil {
+		req.SetQueryParam("page", strconv.Itoa(*r.Page))
+	}
+
+	if r.Sort != nil {
+		req.SetQueryParam("sort", string(*r.Sort))
+	}
+
+	resp, err := req.Get("beatmapsets/events")
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Result().(*BeatmapsetEventsResponse), nil
+}
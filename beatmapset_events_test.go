@@ -0,0 +1,69 @@
+package gosu
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newQueryRecordingClient returns a client whose requests are answered with body, and the query of the last request.
+func newQueryRecordingClient(t *testing.T, body string) (*Client, *url.Values) {
+	var query url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client := newClient(server.Client())
+	client.httpClient.SetBaseURL(server.URL)
+
+	return client, &query
+}
+
+func TestBeatmapsetEventsRequestQuery(t *testing.T) {
+	client, query := newQueryRecordingClient(t, `{"events": [], "users": []}`)
+
+	_, err := client.GetBeatmapsetEvents().
+		SetUser(2).
+		AddType(BeatmapsetEventNominate).
+		AddType(BeatmapsetEventDisqualify).
+		SetBeatmapsetID(45).
+		SetMinDate(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)).
+		SetLimit(50).
+		SetPage(3).
+		SetSort(SortDescending).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	want := url.Values{
+		"user":          {"2"},
+		"types[]":       {"nominate", "disqualify"},
+		"beatmapset_id": {"45"},
+		"min_date":      {"2024-01-02"},
+		"limit":         {"50"},
+		"page":          {"3"},
+		"sort":          {"id_desc"},
+	}
+	if !reflect.DeepEqual(*query, want) {
+		t.Errorf("query = %v, want %v", *query, want)
+	}
+}
+
+func TestBeatmapsetEventsRequestInvalidLimit(t *testing.T) {
+	client, _ := newQueryRecordingClient(t, `{}`)
+
+	for _, limit := range []int{0, 4, 51} {
+		if _, err := client.GetBeatmapsetEvents().SetLimit(limit).Build(); !errors.Is(err, ErrInvalidLimit) {
+			t.Errorf("Build() with limit %d error = %v, want %v", limit, err, ErrInvalidLimit)
+		}
+	}
+}
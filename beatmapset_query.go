@@ -0,0 +1,146 @@
+package gosu
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchOperator compares a beatmapset attribute to a value in the advanced search syntax.
+type SearchOperator string
+
+const (
+	SearchEqual          SearchOperator = "="
+	SearchLess           SearchOperator = "<"
+	SearchLessOrEqual    SearchOperator = "<="
+	SearchGreater        SearchOperator = ">"
+	SearchGreaterOrEqual SearchOperator = ">="
+)
+
+// BeatmapsetQuery builds a beatmapset search query using the advanced search syntax, such as `ar>9 stars<6 length<120`.
+type BeatmapsetQuery struct {
+	terms []string
+	text  []string
+}
+
+func NewBeatmapsetQuery() *BeatmapsetQuery {
+	return &BeatmapsetQuery{}
+}
+
+// searchOperatorPattern matches the operators osu-web reads as a filter after any word, such as in `ar>9` or
+// `status=ranked`. Quoting does not stop it, since a word boundary still follows the quote.
+var searchOperatorPattern = regexp.MustCompile(`(\w)[=:<>]+`)
+
+// Text adds free text to the query. Quotes are removed so the text cannot end a quoted filter, and the operators
+// =, :, < and > after a word are replaced with spaces, because osu-web has no way to search for them literally.
+func (q *BeatmapsetQuery) Text(text string) *BeatmapsetQuery {
+	text = searchOperatorPattern.ReplaceAllString(strings.ReplaceAll(text, `"`, ""), "$1 ")
+
+	if words := strings.Fields(text); len(words) > 0 {
+		q.text = append(q.text, strings.Join(words, " "))
+	}
+	return q
+}
+
+func (q *BeatmapsetQuery) number(key string, op SearchOperator, value float64) *BeatmapsetQuery {
+	q.terms = append(q.terms, key+string(op)+strconv.FormatFloat(value, 'f', -1, 64))
+	return q
+}
+
+func (q *BeatmapsetQuery) date(key string, op SearchOperator, value time.Time) *BeatmapsetQuery {
+	q.terms = append(q.terms, key+string(op)+value.Format("2006-01-02"))
+	return q
+}
+
+// quoted adds a string filter, quoting the value so it may contain spaces.
+func (q *BeatmapsetQuery) quoted(key string, value string) *BeatmapsetQuery {
+	value = strings.ReplaceAll(value, `"`, "")
+	q.terms = append(q.terms, key+`="`+value+`"`)
+	return q
+}
+
+func (q *BeatmapsetQuery) ApproachRate(op SearchOperator, value float64) *BeatmapsetQuery {
+	return q.number("ar", op, value)
+}
+
+func (q *BeatmapsetQuery) CircleSize(op SearchOperator, value float64) *BeatmapsetQuery {
+	return q.number("cs", op, value)
+}
+
+func (q *BeatmapsetQuery) OverallDifficulty(op SearchOperator, value float64) *BeatmapsetQuery {
+	return q.number("od", op, value)
+}
+
+func (q *BeatmapsetQuery) DrainRate(op SearchOperator, value float64) *BeatmapsetQuery {
+	return q.number("hp", op, value)
+}
+
+func (q *BeatmapsetQuery) Stars(op SearchOperator, value float64) *BeatmapsetQuery {
+	return q.number("stars", op, value)
+}
+
+func (q *BeatmapsetQuery) BPM(op SearchOperator, value float64) *BeatmapsetQuery {
+	return q.number("bpm", op, value)
+}
+
+// Length filters by the drain length of beatmaps, rounded down to the second.
+func (q *BeatmapsetQuery) Length(op SearchOperator, value time.Duration) *BeatmapsetQuery {
+	return q.number("length", op, float64(value/time.Second))
+}
+
+// Keys filters mania beatmaps by key count.
+func (q *BeatmapsetQuery) Keys(op SearchOperator, value int) *BeatmapsetQuery {
+	return q.number("keys", op, float64(value))
+}
+
+func (q *BeatmapsetQuery) Divisor(op SearchOperator, value int) *BeatmapsetQuery {
+	return q.number("divisor", op, float64(value))
+}
+
+func (q *BeatmapsetQuery) Status(status RankStatus) *BeatmapsetQuery {
+	q.terms = append(q.terms, "status="+strings.ToLower(status.String()))
+	return q
+}
+
+func (q *BeatmapsetQuery) Ranked(op SearchOperator, value time.Time) *BeatmapsetQuery {
+	return q.date("ranked", op, value)
+}
+
+func (q *BeatmapsetQuery) Created(op SearchOperator, value time.Time) *BeatmapsetQuery {
+	return q.date("created", op, value)
+}
+
+func (q *BeatmapsetQuery) Updated(op SearchOperator, value time.Time) *BeatmapsetQuery {
+	return q.date("updated", op, value)
+}
+
+func (q *BeatmapsetQuery) Artist(artist string) *BeatmapsetQuery {
+	return q.quoted("artist", artist)
+}
+
+func (q *BeatmapsetQuery) Title(title string) *BeatmapsetQuery {
+	return q.quoted("title", title)
+}
+
+func (q *BeatmapsetQuery) Creator(creator string) *BeatmapsetQuery {
+	return q.quoted("creator", creator)
+}
+
+func (q *BeatmapsetQuery) Source(source string) *BeatmapsetQuery {
+	return q.quoted("source", source)
+}
+
+func (q *BeatmapsetQuery) Tag(tag string) *BeatmapsetQuery {
+	return q.quoted("tag", tag)
+}
+
+// Difficulty filters by difficulty name.
+func (q *BeatmapsetQuery) Difficulty(difficulty string) *BeatmapsetQuery {
+	return q.quoted("difficulty", difficulty)
+}
+
+// String returns the query, with filters before free text.
+func (q *BeatmapsetQuery) String() string {
+	return strings.Join(append(append([]string(nil), q.terms...), q.text...), " ")
+}
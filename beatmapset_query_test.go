@@ -0,0 +1,67 @@
+package gosu
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBeatmapsetQuery(t *testing.T) {
+	date := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query *BeatmapsetQuery
+		want  string
+	}{
+		{"empty", NewBeatmapsetQuery(), ""},
+		{
+			name:  "numbers",
+			query: NewBeatmapsetQuery().ApproachRate(SearchGreater, 9).Stars(SearchLess, 6.5).Length(SearchLessOrEqual, 2*time.Minute+30500*time.Millisecond),
+			want:  "ar>9 stars<6.5 length<=150",
+		},
+		{
+			name:  "keys and divisor",
+			query: NewBeatmapsetQuery().Keys(SearchEqual, 7).Divisor(SearchGreaterOrEqual, 4),
+			want:  "keys=7 divisor>=4",
+		},
+		{
+			name:  "dates and status",
+			query: NewBeatmapsetQuery().Ranked(SearchGreaterOrEqual, date).Status(RankStatusLoved),
+			want:  "ranked>=2024-03-05 status=loved",
+		},
+		{
+			name:  "quoted strings",
+			query: NewBeatmapsetQuery().Artist(`The "Quote" Band`).Difficulty("Extra Hard"),
+			want:  `artist="The Quote Band" difficulty="Extra Hard"`,
+		},
+		{
+			name:  "filters before text",
+			query: NewBeatmapsetQuery().Text("camellia").CircleSize(SearchLess, 4),
+			want:  "cs<4 camellia",
+		},
+		{
+			name:  "operators in text",
+			query: NewBeatmapsetQuery().Text("ar>9 status=ranked re:zero a<=b"),
+			want:  "ar 9 status ranked re zero a b",
+		},
+		{
+			name:  "quoted operators in text",
+			query: NewBeatmapsetQuery().Text(`"ar>9" x-cs<4`),
+			want:  "ar 9 x-cs 4",
+		},
+		{
+			name:  "operators without a key",
+			query: NewBeatmapsetQuery().Text("<3 = :)"),
+			want:  "<3 = :)",
+		},
+		{"blank text", NewBeatmapsetQuery().Text(`  "" `), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package gosu
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// BeatmapsetStatusEvent is emitted when the status of a beatmapset changes.
+type BeatmapsetStatusEvent struct {
+	Beatmapset Beatmapset
+	// From is the previous status, or nil if the beatmapset had not been seen before.
+	From *RankStatus
+	To   RankStatus
+	// Disqualified is true when a qualified beatmapset was moved back to pending.
+	Disqualified bool
+	Nominations  BeatmapsetNominations
+	// Time is when the transition happened: the ranked date for qualified, ranked and loved beatmapsets, and the
+	// disqualify event for disqualifications. It falls back to DetectedAt when neither is available.
+	Time time.Time
+	// DetectedAt is when the watcher noticed the transition.
+	DetectedAt time.Time
+}
+
+// BeatmapsetState is what a BeatmapsetWatcher remembers about a beatmapset between polls.
+type BeatmapsetState struct {
+	Status      RankStatus
+	Nominations BeatmapsetNominations
+	// ChangedAt is when the current status was first observed.
+	ChangedAt time.Time
+}
+
+// BeatmapsetStore persists the state of watched beatmapsets.
+type BeatmapsetStore interface {
+	// Load returns the state of a beatmapset, or nil if it has never been seen.
+	Load(beatmapset int) (*BeatmapsetState, error)
+	Save(beatmapset int, state *BeatmapsetState) error
+}
+
+// MemoryBeatmapsetStore keeps beatmapset state in memory.
+type MemoryBeatmapsetStore struct {
+	memoryStore[int, BeatmapsetState]
+}
+
+func NewMemoryBeatmapsetStore() *MemoryBeatmapsetStore {
+	return &MemoryBeatmapsetStore{}
+}
+
+// BeatmapsetWatcher polls beatmapset listings and emits status transitions, such as a set being qualified,
+// ranked, loved or disqualified.
+type BeatmapsetWatcher struct {
+	client *Client
+	Store  BeatmapsetStore
+	// Statuses are the listings searched on every poll.
+	Statuses []RankStatus
+	Mode     *Ruleset
+	// Disqualifications enables polling discussions on disqualified beatmapsets to detect disqualifications.
+	Disqualifications bool
+	Interval          time.Duration
+	// OnError handles failed polls so Run keeps watching. If it is nil, Run returns the first one.
+	OnError func(err error)
+
+	polled bool
+}
+
+// NewBeatmapsetWatcher creates a watcher for qualified, ranked and loved beatmapsets and disqualifications.
+// A nil store keeps what it has seen in memory.
+func (c *Client) NewBeatmapsetWatcher(store BeatmapsetStore, interval time.Duration) *BeatmapsetWatcher {
+	if store == nil {
+		store = NewMemoryBeatmapsetStore()
+	}
+
+	return &BeatmapsetWatcher{
+		client:            c,
+		Store:             store,
+		Statuses:          []RankStatus{RankStatusQualified, RankStatusRanked, RankStatusLoved},
+		Disqualifications: true,
+		Interval:          interval,
+	}
+}
+
+func (w *BeatmapsetWatcher) SetMode(mode Ruleset) *BeatmapsetWatcher {
+	w.Mode = &mode
+	return w
+}
+
+// beatmapsets returns the beatmapsets currently listed under the watched statuses
+// and with discussions on disqualified beatmapsets, in the order they were found.
+func (w *BeatmapsetWatcher) beatmapsets() ([]Beatmapset, error) {
+	var result []Beatmapset
+	seen := make(map[int]bool)
+
+	add := func(beatmapset Beatmapset) {
+		if seen[beatmapset.ID] {
+			return
+		}
+		seen[beatmapset.ID] = true
+		result = append(result, beatmapset)
+	}
+
+	for _, status := range w.Statuses {
+		req := w.client.GetBeatmapsetWithSearch().SetStatus(status).SetNSFW(true)
+		if w.Mode != nil {
+			req.SetMode(*w.Mode)
+		}
+
+		resp, err := req.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, beatmapset := range resp.Beatmapsets {
+			add(beatmapset.Beatmapset)
+		}
+	}
+
+	if w.Disqualifications {
+		resp, err := w.client.GetDiscussions().
+			SetBeatmapsetStatus(DiscussionStatusDisqualified).
+			SetSort(SortDescending).
+			Build()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, beatmapset := range resp.Beatmapsets {
+			add(beatmapset)
+		}
+	}
+
+	return result, nil
+}
+
+// Poll fetches the watched listings and returns the status transitions since the previous poll.
+// Beatmapsets seen for the first time on the first poll are only recorded.
+func (w *BeatmapsetWatcher) Poll() ([]BeatmapsetStatusEvent, error) {
+	beatmapsets, err := w.beatmapsets()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	var events []BeatmapsetStatusEvent
+
+	for _, beatmapset := range beatmapsets {
+		status, err := parseBeatmapsetStatus(beatmapset.Status)
+		if err != nil {
+			return events, err
+		}
+
+		previous, err := w.Store.Load(beatmapset.ID)
+		if err != nil {
+			return events, err
+		}
+
+		state := &BeatmapsetState{Status: status, Nominations: beatmapset.NominationsSummary, ChangedAt: now}
+
+		if previous != nil && previous.Status == status {
+			state.ChangedAt = previous.ChangedAt
+		} else if previous != nil || w.polled {
+			event := BeatmapsetStatusEvent{
+				Beatmapset:  beatmapset,
+				To:          status,
+				Nominations: beatmapset.NominationsSummary,
+				Time:        now,
+				DetectedAt:  now,
+			}
+
+			if previous != nil {
+				from := previous.Status
+				event.From = &from
+				event.Disqualified = from == RankStatusQualified && status == RankStatusPending
+			}
+
+			// Sets only show up under disqualification discussions long after the fact,
+			// so unseen sets that are not in a watched listing are not reported.
+			if previous != nil || w.watches(status) {
+				if err := w.setTransitionTime(&event); err != nil {
+					return events, err
+				}
+				events = append(events, event)
+			}
+		}
+
+		if err := w.Store.Save(beatmapset.ID, state); err != nil {
+			return events, err
+		}
+	}
+
+	w.polled = true
+
+	return events, nil
+}
+
+func (w *BeatmapsetWatcher) watches(status RankStatus) bool {
+	for _, s := range w.Statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setTransitionTime sets the time of the event to when the beatmapset changed status, if it is known.
+func (w *BeatmapsetWatcher) setTransitionTime(event *BeatmapsetStatusEvent) error {
+	switch {
+	case event.Disqualified:
+		resp, err := w.client.GetBeatmapsetEvents().
+			SetBeatmapsetID(event.Beatmapset.ID).
+			AddType(BeatmapsetEventDisqualify).
+			SetSort(SortDescending).
+			Build()
+		if err != nil {
+			return err
+		}
+
+		if len(resp.Events) > 0 {
+			event.Time = resp.Events[0].CreatedAt
+		}
+	case event.To == RankStatusQualified || event.To == RankStatusRanked || event.To == RankStatusLoved:
+		if event.Beatmapset.RankedDate != nil {
+			event.Time = *event.Beatmapset.RankedDate
+		}
+	}
+
+	return nil
+}
+
+// Run polls every Interval and sends events on the channel until the context is cancelled.
+func (w *BeatmapsetWatcher) Run(ctx context.Context, events chan<- BeatmapsetStatusEvent) error {
+	return runPollLoop(ctx, w.Interval, events, func() ([]BeatmapsetStatusEvent, error) {
+		newEvents, err := w.Poll()
+		if err != nil && w.OnError != nil {
+			w.OnError(err)
+			err = nil
+		}
+
+		return newEvents, err
+	})
+}
+
+// parseBeatmapsetStatus parses the status of a beatmapset as returned by the API, which uses "wip" for RankStatusWIP.
+func parseBeatmapsetStatus(status string) (RankStatus, error) {
+	if strings.EqualFold(status, RankStatusWIP.String()) {
+		return RankStatusWIP, nil
+	}
+
+	return ParseRankStatus(status)
+}
@@ -1,6 +1,8 @@
 package gosu
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -58,6 +60,37 @@ const (
 	SortTitle                           = "title"
 )
 
+// BeatmapsetSearchGeneral is a general filter of the beatmapset search.
+type BeatmapsetSearchGeneral string
+
+const (
+	SearchGeneralRecommended     BeatmapsetSearchGeneral = "recommended"
+	SearchGeneralConverts        BeatmapsetSearchGeneral = "converts"
+	SearchGeneralFollows         BeatmapsetSearchGeneral = "follows"
+	SearchGeneralSpotlights      BeatmapsetSearchGeneral = "spotlights"
+	SearchGeneralFeaturedArtists BeatmapsetSearchGeneral = "featured_artists"
+)
+
+// BeatmapsetSearchPlayed filters beatmapsets by whether the user has played them.
+type BeatmapsetSearchPlayed string
+
+const (
+	SearchPlayed   BeatmapsetSearchPlayed = "played"
+	SearchUnplayed BeatmapsetSearchPlayed = "unplayed"
+)
+
+// searchGrades maps grades to the names used by the rank achieved filter.
+var searchGrades = map[Grade]string{
+	GradeSSH: "XH",
+	GradeSS:  "X",
+	GradeSH:  "SH",
+	GradeS:   "S",
+	GradeA:   "A",
+	GradeB:   "B",
+	GradeC:   "C",
+	GradeD:   "D",
+}
+
 type BeatmapsetCompact struct {
 	Artist         string          `json:"artist"`
 	ArtistUnicode  *string         `json:"artist_unicode"`
@@ -141,8 +174,9 @@ type BeatmapsetLanguage struct {
 }
 
 type BeatmapsetSearchResponse struct {
-	Cursor      *Cursor `json:"cursor"`
-	Beatmapsets []struct {
+	Cursor       *Cursor `json:"cursor"`
+	CursorString *string `json:"cursor_string"`
+	Beatmapsets  []struct {
 		Beatmapset
 		Beatmaps []struct {
 			Beatmap
@@ -192,7 +226,69 @@ func (c *Client) GetBeatmapsetWithID(beatmapsetID int) *BeatmapsetWithIDRequest
 
 func (r *BeatmapsetWithIDRequest) Build() (*LookupBeatmapsetResponse, error) {
 	req := r.client.httpClient.R().SetResult(&LookupBeatmapsetResponse{})
-	req.SetQueryParam("beatmap_id", strconv.Itoa(r.BeatmapsetID))
+	req.SetPathParam("beatmapset", strconv.Itoa(r.BeatmapsetID))
+
+	resp, err := req.Get("beatmapsets/{beatmapset}")
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result().(*LookupBeatmapsetResponse), nil
+}
+
+type LookupBeatmapsetRequest struct {
+	client    *Client
+	BeatmapID *int
+	Checksum  *string
+	Filename  *string
+}
+
+// LookupBeatmapset returns the beatmapset containing a beatmap, found by the beatmap's ID, checksum or filename.
+func (c *Client) LookupBeatmapset() *LookupBeatmapsetRequest {
+	return &LookupBeatmapsetRequest{client: c}
+}
+
+func (r *LookupBeatmapsetRequest) SetBeatmapID(beatmapID int) *LookupBeatmapsetRequest {
+	r.BeatmapID = &beatmapID
+	return r
+}
+
+// SetChecksum looks up the beatmap by the MD5 checksum of its .osu file. This costs an extra request.
+func (r *LookupBeatmapsetRequest) SetChecksum(checksum string) *LookupBeatmapsetRequest {
+	r.Checksum = &checksum
+	return r
+}
+
+// SetFilename looks up the beatmap by the name of its .osu file. This costs an extra request.
+func (r *LookupBeatmapsetRequest) SetFilename(filename string) *LookupBeatmapsetRequest {
+	r.Filename = &filename
+	return r
+}
+
+func (r *LookupBeatmapsetRequest) Build() (*LookupBeatmapsetResponse, error) {
+	beatmapID := r.BeatmapID
+
+	if beatmapID == nil {
+		if r.Checksum == nil && r.Filename == nil {
+			return nil, errors.New("beatmap ID, checksum or filename is required")
+		}
+
+		lookup := r.client.LookupBeatmap()
+		if r.Checksum != nil {
+			lookup.SetChecksum(*r.Checksum)
+		}
+		if r.Filename != nil {
+			lookup.SetFilename(*r.Filename)
+		}
+
+		beatmap, err := lookup.Build()
+		if err != nil {
+			return nil, err
+		}
+		beatmapID = &beatmap.ID
+	}
+
+	req := r.client.httpClient.R().SetResult(&LookupBeatmapsetResponse{})
+	req.SetQueryParam("beatmap_id", strconv.Itoa(*beatmapID))
 
 	resp, err := req.Get("beatmapsets/lookup")
 	if err != nil {
@@ -201,24 +297,71 @@ func (r *BeatmapsetWithIDRequest) Build() (*LookupBeatmapsetResponse, error) {
 	return resp.Result().(*LookupBeatmapsetResponse), nil
 }
 
+type FavouriteBeatmapsetResponse struct {
+	FavouriteCount int `json:"favourite_count"`
+}
+
+type FavouriteBeatmapsetRequest struct {
+	client     *Client
+	Beatmapset int
+	Favourite  bool
+}
+
+// FavouriteBeatmapset adds a beatmapset to the favourites of the authenticated user.
+// It needs a client created with NewClientWithToken.
+func (c *Client) FavouriteBeatmapset(beatmapset int) *FavouriteBeatmapsetRequest {
+	return &FavouriteBeatmapsetRequest{client: c, Beatmapset: beatmapset, Favourite: true}
+}
+
+// UnfavouriteBeatmapset removes a beatmapset from the favourites of the authenticated user.
+// It needs a client created with NewClientWithToken.
+func (c *Client) UnfavouriteBeatmapset(beatmapset int) *FavouriteBeatmapsetRequest {
+	return &FavouriteBeatmapsetRequest{client: c, Beatmapset: beatmapset, Favourite: false}
+}
+
+func (r *FavouriteBeatmapsetRequest) Build() (*FavouriteBeatmapsetResponse, error) {
+	req := r.client.httpClient.R().SetResult(&FavouriteBeatmapsetResponse{})
+	req.SetPathParam("beatmapset", strconv.Itoa(r.Beatmapset))
+
+	action := "unfavourite"
+	if r.Favourite {
+		action = "favourite"
+	}
+
+	resp, err := req.SetBody(map[string]string{"action": action}).Post("beatmapsets/{beatmapset}/favourites")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Result().(*FavouriteBeatmapsetResponse), nil
+}
+
 type searchRankStatus struct {
 	Any      bool
 	Specific *RankStatus
 }
 
 type BeatmapsetWithSearchRequest struct {
-	client     *Client
-	Query      *string
-	Mode       *Ruleset
-	status     *searchRankStatus
-	Genre      *Genre
-	Language   *Language
-	Video      bool
-	Storyboard bool
-	NSFW       bool
-	Sort       *BeatmapsetSearchSort
-	Descending bool
-	Cursor     *Cursor
+	client       *Client
+	Query        *string
+	Mode         *Ruleset
+	status       *searchRankStatus
+	Genre        *Genre
+	Language     *Language
+	Video        bool
+	Storyboard   bool
+	NSFW         bool
+	General      []BeatmapsetSearchGeneral
+	Played       *BeatmapsetSearchPlayed
+	RankAchieved []Grade
+	Sort         *BeatmapsetSearchSort
+	Descending   bool
+	Cursor       *Cursor
+	CursorString *string
 }
 
 // GetBeatmapsetWithSearch returns a beatmapset, using a search query.
@@ -231,6 +374,11 @@ func (r *BeatmapsetWithSearchRequest) SetQuery(query string) *BeatmapsetWithSear
 	return r
 }
 
+// SetSearchQuery sets the query to the advanced search syntax built by query.
+func (r *BeatmapsetWithSearchRequest) SetSearchQuery(query *BeatmapsetQuery) *BeatmapsetWithSearchRequest {
+	return r.SetQuery(query.String())
+}
+
 func (r *BeatmapsetWithSearchRequest) SetMode(mode Ruleset) *BeatmapsetWithSearchRequest {
 	r.Mode = &mode
 	return r
@@ -242,12 +390,7 @@ func (r *BeatmapsetWithSearchRequest) AnyStatus() *BeatmapsetWithSearchRequest {
 }
 
 func (r *BeatmapsetWithSearchRequest) SetStatus(status RankStatus) *BeatmapsetWithSearchRequest {
-	if status == RankStatusRanked {
-		temp := RankStatusPending
-		r.status = &searchRankStatus{Any: false, Specific: &temp}
-	} else {
-		r.status = &searchRankStatus{Any: false, Specific: &status}
-	}
+	r.status = &searchRankStatus{Any: false, Specific: &status}
 	return r
 }
 
@@ -276,17 +419,45 @@ func (r *BeatmapsetWithSearchRequest) SetNSFW(nsfw bool) *BeatmapsetWithSearchRe
 	return r
 }
 
+func (r *BeatmapsetWithSearchRequest) AddGeneral(general BeatmapsetSearchGeneral) *BeatmapsetWithSearchRequest {
+	r.General = append(r.General, general)
+	return r
+}
+
+func (r *BeatmapsetWithSearchRequest) SetGeneral(general []BeatmapsetSearchGeneral) *BeatmapsetWithSearchRequest {
+	r.General = general
+	return r
+}
+
+// SetPlayed filters beatmapsets by whether the authenticated user has played them. It requires osu!supporter.
+func (r *BeatmapsetWithSearchRequest) SetPlayed(played BeatmapsetSearchPlayed) *BeatmapsetWithSearchRequest {
+	r.Played = &played
+	return r
+}
+
+// SetRankAchieved filters beatmapsets by the grades the authenticated user achieved on them. It requires osu!supporter.
+func (r *BeatmapsetWithSearchRequest) SetRankAchieved(grades ...Grade) *BeatmapsetWithSearchRequest {
+	r.RankAchieved = grades
+	return r
+}
+
 func (r *BeatmapsetWithSearchRequest) SortBy(sort BeatmapsetSearchSort, descending bool) *BeatmapsetWithSearchRequest {
 	r.Sort = &sort
 	r.Descending = descending
 	return r
 }
 
+// SetCursor sets the cursor of the page to fetch, as returned in the Cursor of a previous response.
 func (r *BeatmapsetWithSearchRequest) SetCursor(cursor *Cursor) *BeatmapsetWithSearchRequest {
 	r.Cursor = cursor
 	return r
 }
 
+func (r *BeatmapsetWithSearchRequest) SetCursorString(cursorString string) *BeatmapsetWithSearchRequest {
+	r.CursorString = &cursorString
+	return r
+}
+
 func (r *BeatmapsetWithSearchRequest) Build() (*BeatmapsetSearchResponse, error) {
 	req := r.client.httpClient.R().SetResult(&BeatmapsetSearchResponse{})
 
@@ -332,6 +503,43 @@ func (r *BeatmapsetWithSearchRequest) Build() (*BeatmapsetSearchResponse, error)
 
 	req.SetQueryParam("nsfw", strconv.FormatBool(r.NSFW))
 
+	if len(r.General) > 0 {
+		general := make([]string, len(r.General))
+		for i, g := range r.General {
+			general[i] = string(g)
+		}
+		req.SetQueryParam("c", strings.Join(general, "."))
+	}
+
+	if r.Played != nil {
+		req.SetQueryParam("played", string(*r.Played))
+	}
+
+	if len(r.RankAchieved) > 0 {
+		grades := make([]string, 0, len(r.RankAchieved))
+		for _, grade := range r.RankAchieved {
+			name, ok := searchGrades[grade]
+			if !ok {
+				return nil, fmt.Errorf("grade %s cannot be searched for", grade)
+			}
+			grades = append(grades, name)
+		}
+		req.SetQueryParam("r", strings.Join(grades, "."))
+	}
+
+	if r.CursorString != nil {
+		req.SetQueryParam("cursor_string", *r.CursorString)
+	} else if r.Cursor != nil {
+		cursor, ok := (*r.Cursor).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unsupported cursor type %T", *r.Cursor)
+		}
+
+		for key, value := range cursor {
+			req.SetQueryParam("cursor["+key+"]", formatCursorValue(value))
+		}
+	}
+
 	if r.Sort != nil {
 		var sort strings.Builder
 		sort.WriteString(string(*r.Sort))
@@ -352,3 +560,15 @@ func (r *BeatmapsetWithSearchRequest) Build() (*BeatmapsetSearchResponse, error)
 
 	return resp.Result().(*BeatmapsetSearchResponse), nil
 }
+
+// formatCursorValue formats a cursor value decoded from JSON as a query parameter.
+func formatCursorValue(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -3,28 +3,61 @@ package gosu
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/go-resty/resty/v2"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
+	"net/http"
 	"strconv"
 )
 
+// Endpoint is the OAuth endpoint of osu!.
+var Endpoint = oauth2.Endpoint{
+	AuthURL:  "https://osu.ppy.sh/oauth/authorize",
+	TokenURL: "https://osu.ppy.sh/oauth/token",
+}
+
 type Client struct {
 	httpClient *resty.Client
 }
 
 // NewClient creates a gosu client with client credentials.
 func NewClient(clientID int, clientSecret string) (*Client, error) {
-	client := &Client{}
-
 	ctx := context.Background()
 	oauthConfig := clientcredentials.Config{
 		ClientID:     strconv.Itoa(clientID),
 		ClientSecret: clientSecret,
-		TokenURL:     "https://osu.ppy.sh/oauth/token",
+		TokenURL:     Endpoint.TokenURL,
 		Scopes:       []string{"public"},
 	}
 
-	client.httpClient = resty.NewWithClient(oauthConfig.Client(ctx)).SetBaseURL("https://osu.ppy.sh/api/v2")
+	return newClient(oauthConfig.Client(ctx)), nil
+}
+
+// NewAuthorizationCodeConfig returns the config of the authorization code flow, used to act on behalf of a user.
+// Send the user to its AuthCodeURL, then exchange the code they are redirected back with for a token using Exchange.
+func NewAuthorizationCodeConfig(clientID int, clientSecret string, redirectURL string, scopes ...string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     strconv.Itoa(clientID),
+		ClientSecret: clientSecret,
+		Endpoint:     Endpoint,
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+	}
+}
+
+// NewClientWithToken creates a gosu client that acts as the user who authorized the token.
+// The token is refreshed with config when it expires.
+func NewClientWithToken(config *oauth2.Config, token *oauth2.Token) (*Client, error) {
+	ctx := context.Background()
+
+	return newClient(config.Client(ctx, token)), nil
+}
+
+func newClient(httpClient *http.Client) *Client {
+	client := &Client{}
+
+	client.httpClient = resty.NewWithClient(httpClient).SetBaseURL("https://osu.ppy.sh/api/v2")
 
 	client.httpClient.OnAfterResponse(func(c *resty.Client, resp *resty.Response) error {
 		switch resp.StatusCode() {
@@ -35,5 +68,14 @@ func NewClient(clientID int, clientSecret string) (*Client, error) {
 		return nil
 	})
 
-	return client, nil
+	return client
+}
+
+// checkResponse returns an error for error statuses other than 404, which resty does not treat as failures.
+func checkResponse(resp *resty.Response) error {
+	if resp.IsError() {
+		return fmt.Errorf("unexpected status: %s", resp.Status())
+	}
+
+	return nil
 }
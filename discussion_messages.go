@@ -0,0 +1,205 @@
+package gosu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const SystemMessageTypeResolved = "resolved"
+
+// SystemMessage is the message of a system post, such as a discussion being resolved or reopened.
+type SystemMessage struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Resolved returns whether a resolved system message marked the discussion as resolved, and false for other messages.
+func (m SystemMessage) Resolved() (resolved, ok bool) {
+	if m.Type != SystemMessageTypeResolved {
+		return false, false
+	}
+
+	resolved, ok = m.Value.(bool)
+	return resolved, ok
+}
+
+type ReviewBlockType string
+
+const (
+	ReviewBlockParagraph ReviewBlockType = "paragraph"
+	ReviewBlockEmbed     ReviewBlockType = "embed"
+)
+
+// ReviewBlock is a block of a review document. Paragraphs have Text, and embeds reference the discussion they
+// created by DiscussionID. DiscussionType, BeatmapID and Timestamp are only used when submitting a review.
+type ReviewBlock struct {
+	Type           ReviewBlockType `json:"type"`
+	Text           string          `json:"text,omitempty"`
+	DiscussionID   *int            `json:"discussion_id,omitempty"`
+	DiscussionType *MessageType    `json:"discussion_type,omitempty"`
+	BeatmapID      *int            `json:"beatmap_id,omitempty"`
+	Timestamp      *int            `json:"timestamp,omitempty"`
+}
+
+// ReviewDocument is the parsed message of the first post of a review.
+type ReviewDocument struct {
+	Blocks []ReviewBlock
+}
+
+// ParseReview parses a review document from the JSON array of blocks a review post is made of.
+func ParseReview(text string) (*ReviewDocument, error) {
+	var blocks []ReviewBlock
+	if err := json.Unmarshal([]byte(text), &blocks); err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		switch block.Type {
+		case ReviewBlockParagraph:
+		case ReviewBlockEmbed:
+			if block.DiscussionID == nil && block.DiscussionType == nil {
+				return nil, errors.New("embed block without discussion")
+			}
+		default:
+			return nil, fmt.Errorf("unknown review block type %q", block.Type)
+		}
+	}
+
+	return &ReviewDocument{Blocks: blocks}, nil
+}
+
+// Paragraphs returns the text of the paragraph blocks.
+func (d *ReviewDocument) Paragraphs() []string {
+	var paragraphs []string
+	for _, block := range d.Blocks {
+		if block.Type == ReviewBlockParagraph {
+			paragraphs = append(paragraphs, block.Text)
+		}
+	}
+	return paragraphs
+}
+
+// DiscussionIDs returns the IDs of the discussions embedded in the review.
+func (d *ReviewDocument) DiscussionIDs() []int {
+	var ids []int
+	for _, block := range d.Blocks {
+		if block.Type == ReviewBlockEmbed && block.DiscussionID != nil {
+			ids = append(ids, *block.DiscussionID)
+		}
+	}
+	return ids
+}
+
+func (d *ReviewDocument) String() string {
+	data, _ := json.Marshal(d.Blocks)
+	return string(data)
+}
+
+// DiscussionMessage is the message of a discussion post. Exactly one of System or Review is set for system
+// posts and reviews. Text is the message as sent by the API, except for system posts where it is empty.
+type DiscussionMessage struct {
+	Text   string
+	System *SystemMessage
+	Review *ReviewDocument
+}
+
+func (m *DiscussionMessage) UnmarshalJSON(data []byte) error {
+	*m = DiscussionMessage{}
+
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var system SystemMessage
+		if err := json.Unmarshal(data, &system); err != nil {
+			return err
+		}
+		m.System = &system
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &m.Text); err != nil {
+		return err
+	}
+
+	trimmed := bytes.TrimSpace([]byte(m.Text))
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if review, err := ParseReview(m.Text); err == nil {
+			m.Review = review
+		}
+	}
+
+	return nil
+}
+
+func (m DiscussionMessage) MarshalJSON() ([]byte, error) {
+	if m.System != nil {
+		return json.Marshal(m.System)
+	}
+
+	if m.Review != nil && m.Text == "" {
+		return json.Marshal(m.Review.String())
+	}
+
+	return json.Marshal(m.Text)
+}
+
+// Timestamps returns the editor timestamps in the message, looking in the paragraphs of reviews.
+func (m DiscussionMessage) Timestamps() []EditorTimestamp {
+	if m.Review != nil {
+		var timestamps []EditorTimestamp
+		for _, paragraph := range m.Review.Paragraphs() {
+			timestamps = append(timestamps, ParseTimestamps(paragraph)...)
+		}
+		return timestamps
+	}
+
+	return ParseTimestamps(m.Text)
+}
+
+// EditorTimestamp is a link to a point in the beatmap editor, such as `01:23:456 (1,2,3)`.
+type EditorTimestamp struct {
+	// Time is the position in the beatmap in milliseconds.
+	Time int
+	// Objects is the selection in parentheses, such as "1,2,3" for combo numbers or "1234|1" for mania notes,
+	// and empty if there is none.
+	Objects string
+	// Text is the timestamp as written in the message.
+	Text string
+}
+
+func (t EditorTimestamp) String() string {
+	if t.Objects == "" {
+		return FormatTimestamp(t.Time)
+	}
+
+	return FormatTimestamp(t.Time) + " (" + t.Objects + ")"
+}
+
+var editorTimestampPattern = regexp.MustCompile(`\b(\d{2,}):([0-5]\d)[:.](\d{3})\b(?: \(([^)]+)\))?`)
+
+// ParseTimestamps returns the editor timestamps in text in the order they appear.
+func ParseTimestamps(text string) []EditorTimestamp {
+	var timestamps []EditorTimestamp
+
+	for _, match := range editorTimestampPattern.FindAllStringSubmatch(text, -1) {
+		minutes, _ := strconv.Atoi(match[1])
+		seconds, _ := strconv.Atoi(match[2])
+		milliseconds, _ := strconv.Atoi(match[3])
+
+		timestamps = append(timestamps, EditorTimestamp{
+			Time:    (minutes*60+seconds)*1000 + milliseconds,
+			Objects: match[4],
+			Text:    match[0],
+		})
+	}
+
+	return timestamps
+}
+
+// FormatTimestamp formats milliseconds as an editor timestamp, such as 01:23:456.
+func FormatTimestamp(milliseconds int) string {
+	return fmt.Sprintf("%02d:%02d:%03d", milliseconds/60000, milliseconds/1000%60, milliseconds%1000)
+}
@@ -0,0 +1,225 @@
+package gosu
+
+import "sort"
+
+// ThreadPost is a discussion post with its author.
+type ThreadPost struct {
+	DiscussionPost
+	// Author is nil if the user was not included in the responses.
+	Author *UserCompact
+}
+
+// Thread is a discussion joined with its posts, author, beatmapset and the difficulty it refers to.
+type Thread struct {
+	Discussion BeatmapsetDiscussion
+	// Posts are ordered oldest first, starting with the post that opened the discussion.
+	Posts      []ThreadPost
+	Author     *UserCompact
+	Beatmapset *BeatmapsetCompact
+	// Beatmap is the difficulty the discussion refers to, or nil for general discussions about the whole beatmapset.
+	Beatmap *Beatmap
+}
+
+// ThreadGroup holds the threads about one difficulty, or the general threads if BeatmapID is nil.
+type ThreadGroup struct {
+	BeatmapID *int
+	Beatmap   *Beatmap
+	// Threads are ordered by timestamp, with threads without one first, then by creation time.
+	Threads []Thread
+}
+
+// AssembleThreads joins discussions with the posts, users, beatmapsets and beatmaps that reference them by ID.
+// Discussions that only appear in posts responses are included too. Threads are ordered like GroupThreads.
+func AssembleThreads(discussions *DiscussionsResponse, posts ...*DiscussionPostsResponse) []Thread {
+	users := make(map[int]*UserCompact)
+	beatmapsets := make(map[int]*BeatmapsetCompact)
+	beatmaps := make(map[int]*Beatmap)
+	postsByDiscussion := make(map[int][]ThreadPost)
+
+	var order []int
+	byID := make(map[int]BeatmapsetDiscussion)
+
+	addDiscussion := func(discussion BeatmapsetDiscussion) {
+		if _, ok := byID[discussion.ID]; !ok {
+			order = append(order, discussion.ID)
+		}
+		byID[discussion.ID] = discussion
+	}
+
+	addUsers := func(list []UserCompact) {
+		for i := range list {
+			users[list[i].ID] = &list[i]
+		}
+	}
+
+	if discussions != nil {
+		addUsers(discussions.Users)
+
+		for i := range discussions.Beatmapsets {
+			beatmapsets[discussions.Beatmapsets[i].ID] = &discussions.Beatmapsets[i].BeatmapsetCompact
+		}
+
+		for i := range discussions.Beatmaps {
+			beatmaps[discussions.Beatmaps[i].ID] = &discussions.Beatmaps[i].Beatmap
+		}
+
+		for _, discussion := range discussions.Discussions {
+			addDiscussion(discussion)
+		}
+	}
+
+	seenPosts := make(map[int]bool)
+
+	for _, response := range posts {
+		if response == nil {
+			continue
+		}
+
+		addUsers(response.Users)
+
+		for i := range response.Beatmapsets {
+			if _, ok := beatmapsets[response.Beatmapsets[i].ID]; !ok {
+				beatmapsets[response.Beatmapsets[i].ID] = &response.Beatmapsets[i]
+			}
+		}
+
+		for _, discussion := range response.Discussions {
+			if _, ok := byID[discussion.ID]; !ok {
+				addDiscussion(discussion)
+			}
+		}
+
+		for _, post := range response.Posts {
+			if seenPosts[post.ID] {
+				continue
+			}
+			seenPosts[post.ID] = true
+
+			postsByDiscussion[post.BeatmapsetDiscussionID] = append(postsByDiscussion[post.BeatmapsetDiscussionID], ThreadPost{DiscussionPost: post})
+		}
+	}
+
+	threads := make([]Thread, 0, len(order))
+
+	for _, id := range order {
+		discussion := byID[id]
+		thread := Thread{Discussion: discussion, Posts: postsByDiscussion[id]}
+
+		sort.SliceStable(thread.Posts, func(i, j int) bool {
+			a, b := thread.Posts[i], thread.Posts[j]
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			return a.ID < b.ID
+		})
+
+		for i := range thread.Posts {
+			thread.Posts[i].Author = users[thread.Posts[i].UserID]
+		}
+
+		if discussion.UserID != nil {
+			thread.Author = users[*discussion.UserID]
+		}
+
+		if discussion.BeatmapsetID != nil {
+			thread.Beatmapset = beatmapsets[*discussion.BeatmapsetID]
+		}
+
+		if discussion.BeatmapID != nil {
+			thread.Beatmap = beatmaps[*discussion.BeatmapID]
+		}
+
+		threads = append(threads, thread)
+	}
+
+	sortThreads(threads)
+
+	return threads
+}
+
+// GroupThreads groups threads by the difficulty they refer to. General threads come first,
+// followed by difficulties in order of star rating, and difficulties whose beatmap is unknown last.
+func GroupThreads(threads []Thread) []ThreadGroup {
+	var groups []ThreadGroup
+	index := make(map[int]int)
+	general := -1
+
+	for _, thread := range threads {
+		var i int
+
+		if thread.Discussion.BeatmapID == nil {
+			if general < 0 {
+				general = len(groups)
+				groups = append(groups, ThreadGroup{})
+			}
+			i = general
+		} else {
+			id := *thread.Discussion.BeatmapID
+
+			existing, ok := index[id]
+			if !ok {
+				existing = len(groups)
+				index[id] = existing
+				groups = append(groups, ThreadGroup{BeatmapID: &id, Beatmap: thread.Beatmap})
+			}
+			i = existing
+		}
+
+		groups[i].Threads = append(groups[i].Threads, thread)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return beatmapLess(groups[i].BeatmapID, groups[i].Beatmap, groups[j].BeatmapID, groups[j].Beatmap)
+	})
+
+	for i := range groups {
+		sortThreads(groups[i].Threads)
+	}
+
+	return groups
+}
+
+// beatmapLess orders general discussions first, then difficulties by star rating, then difficulties whose beatmap
+// is unknown, with ties broken by ID so the order does not depend on the input.
+func beatmapLess(aID *int, a *Beatmap, bID *int, b *Beatmap) bool {
+	if aID == nil || bID == nil {
+		return aID == nil && bID != nil
+	}
+
+	if (a == nil) != (b == nil) {
+		return a != nil
+	}
+
+	if a != nil && a.DifficultyRating != b.DifficultyRating {
+		return a.DifficultyRating < b.DifficultyRating
+	}
+
+	return *aID < *bID
+}
+
+// sortThreads orders threads by difficulty, then by timestamp with threads without one first, then by creation time.
+func sortThreads(threads []Thread) {
+	sort.SliceStable(threads, func(i, j int) bool {
+		a, b := threads[i].Discussion, threads[j].Discussion
+
+		if !equalIntPtr(a.BeatmapID, b.BeatmapID) {
+			return beatmapLess(a.BeatmapID, threads[i].Beatmap, b.BeatmapID, threads[j].Beatmap)
+		}
+
+		if !equalIntPtr(a.Timestamp, b.Timestamp) {
+			if a.Timestamp == nil || b.Timestamp == nil {
+				return a.Timestamp == nil
+			}
+			return *a.Timestamp < *b.Timestamp
+		}
+
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,55 @@
+package gosu
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupThreadsOrder(t *testing.T) {
+	beatmap := func(starRating float32) *Beatmap {
+		return &Beatmap{BeatmapCompact: BeatmapCompact{DifficultyRating: starRating}}
+	}
+
+	thread := func(id int, beatmapID *int, b *Beatmap) Thread {
+		return Thread{Discussion: BeatmapsetDiscussion{ID: id, BeatmapID: beatmapID}, Beatmap: b}
+	}
+
+	threads := []Thread{
+		thread(1, ptr(5), beatmap(1)),
+		thread(2, ptr(3), nil),
+		thread(3, ptr(1), beatmap(5)),
+		thread(4, nil, nil),
+		thread(5, ptr(2), nil),
+		thread(6, ptr(4), beatmap(1)),
+	}
+
+	// General first, then by star rating and ID, then the difficulties without a beatmap by ID.
+	want := []int{0, 4, 5, 1, 2, 3}
+
+	permutations := [][]int{
+		{0, 1, 2, 3, 4, 5},
+		{5, 4, 3, 2, 1, 0},
+		{1, 3, 5, 0, 2, 4},
+		{2, 0, 4, 5, 3, 1},
+	}
+
+	for _, permutation := range permutations {
+		input := make([]Thread, len(threads))
+		for i, j := range permutation {
+			input[i] = threads[j]
+		}
+
+		var got []int
+		for _, group := range GroupThreads(input) {
+			id := 0
+			if group.BeatmapID != nil {
+				id = *group.BeatmapID
+			}
+			got = append(got, id)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GroupThreads(%v) beatmap IDs = %v, want %v", permutation, got, want)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package gosu
 
-import "time"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
 
 type EventType string
 
@@ -20,11 +25,26 @@ const (
 	EventTypeUsernameChange    EventType = "usernameChange"
 )
 
+// Event is implemented by every event type. Use a type switch to access the fields of a specific event.
+type Event interface {
+	Base() EventBase
+}
+
 type EventBase struct {
-	CreatedAt       time.Time `json:"created_at" json:"created_at"`
-	ID              int
-	Type            EventType              `json:"type"`
-	EventAttributes map[string]interface{} `json:",remain"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        int       `json:"id"`
+	Type      EventType `json:"type"`
+}
+
+// Base returns the fields shared by all events.
+func (e EventBase) Base() EventBase {
+	return e
+}
+
+// UnknownEvent holds an event of a type gosu does not know about.
+type UnknownEvent struct {
+	EventBase
+	Raw json.RawMessage
 }
 
 type EventBeatmap struct {
@@ -38,41 +58,49 @@ type EventBeatmapset struct {
 }
 
 type BeatmapPlaycountEvent struct {
+	EventBase
 	Beatmap EventBeatmap `json:"beatmap"`
 	Count   int          `json:"count"`
 }
 
 type BeatmapsetApproveEvent struct {
+	EventBase
 	Approval   RankStatus      `json:"approval"`
 	Beatmapset EventBeatmapset `json:"beatmapset"`
 	User       EventUser       `json:"user"`
 }
 
 type BeatmapsetDeleteEvent struct {
+	EventBase
 	Beatmapset EventBeatmapset `json:"beatmapset"`
 }
 
 type BeatmapsetReviveEvent struct {
+	EventBase
 	Beatmapset EventBeatmapset `json:"beatmapset"`
 	User       EventUser       `json:"user"`
 }
 
 type BeatmapsetUpdateEvent struct {
+	EventBase
 	Beatmapset EventBeatmapset `json:"beatmapset"`
 	User       EventUser       `json:"user"`
 }
 
 type BeatmapsetUploadEvent struct {
+	EventBase
 	Beatmapset EventBeatmapset `json:"beatmapset"`
 	User       EventUser       `json:"user"`
 }
 
 type MedalEvent struct {
+	EventBase
 	Achievement Medal     `json:"achievement"`
 	User        EventUser `json:"user"`
 }
 
 type RankEvent struct {
+	EventBase
 	Grade   Grade        `json:"scoreRank"`
 	Rank    int          `json:"rank"`
 	Mode    Ruleset      `json:"mode"`
@@ -81,24 +109,29 @@ type RankEvent struct {
 }
 
 type RankLostEvent struct {
+	EventBase
 	Mode    Ruleset      `json:"mode"`
 	Beatmap EventBeatmap `json:"beatmap"`
 	User    EventUser    `json:"user"`
 }
 
 type SupportAgainEvent struct {
+	EventBase
 	User EventUser `json:"user"`
 }
 
 type SupportFirstEvent struct {
+	EventBase
 	User EventUser `json:"user"`
 }
 
 type SupportGiftEvent struct {
+	EventBase
 	User EventUser `json:"user"`
 }
 
 type UsernameChangeEvent struct {
+	EventBase
 	User EventUser `json:"user"`
 }
 
@@ -107,3 +140,205 @@ type EventUser struct {
 	URL              string  `json:"url"`
 	PreviousUsername *string `json:"previousUsername,omitempty"`
 }
+
+// decodeEvent decodes an event into the struct matching its type.
+func decodeEvent(data json.RawMessage) (Event, error) {
+	var base EventBase
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, err
+	}
+
+	var event Event
+	switch base.Type {
+	case EventTypeAchievement:
+		event = &MedalEvent{}
+	case EventTypeBeatmapPlaycount:
+		event = &BeatmapPlaycountEvent{}
+	case EventTypeBeatmapsetApprove:
+		event = &BeatmapsetApproveEvent{}
+	case EventTypeBeatmapsetDelete:
+		event = &BeatmapsetDeleteEvent{}
+	case EventTypeBeatmapsetRevive:
+		event = &BeatmapsetReviveEvent{}
+	case EventTypeBeatmapsetUpdate:
+		event = &BeatmapsetUpdateEvent{}
+	case EventTypeBeatmapsetUpload:
+		event = &BeatmapsetUploadEvent{}
+	case EventTypeRank:
+		event = &RankEvent{}
+	case EventTypeRankLost:
+		event = &RankLostEvent{}
+	case EventTypeUserSupportAgain:
+		event = &SupportAgainEvent{}
+	case EventTypeUserSupportFirst:
+		event = &SupportFirstEvent{}
+	case EventTypeUserSupportGift:
+		event = &SupportGiftEvent{}
+	case EventTypeUsernameChange:
+		event = &UsernameChangeEvent{}
+	default:
+		return &UnknownEvent{EventBase: base, Raw: data}, nil
+	}
+
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func decodeEvents(data []json.RawMessage) ([]Event, error) {
+	result := make([]Event, 0, len(data))
+
+	for _, raw := range data {
+		event, err := decodeEvent(raw)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, event)
+	}
+
+	return result, nil
+}
+
+type EventsResponse struct {
+	Events       []Event
+	CursorString *string
+}
+
+type eventsResponse struct {
+	Events       []json.RawMessage `json:"events"`
+	CursorString *string           `json:"cursor_string"`
+}
+
+type EventsRequest struct {
+	client       *Client
+	Sort         *Sort
+	CursorString *string
+}
+
+// GetEvents returns a collection of events across the site, newest first unless sorted otherwise.
+func (c *Client) GetEvents() *EventsRequest {
+	return &EventsRequest{client: c}
+}
+
+func (r *EventsRequest) SetSort(sort Sort) *EventsRequest {
+	r.Sort = &sort
+	return r
+}
+
+func (r *EventsRequest) SetCursorString(cursorString string) *EventsRequest {
+	r.CursorString = &cursorString
+	return r
+}
+
+func (r *EventsRequest) Build() (*EventsResponse, error) {
+	req := r.client.httpClient.R().SetResult(&eventsResponse{})
+
+	if r.Sort != nil {
+		req.SetQueryParam("sort", string(*r.Sort))
+	}
+
+	if r.CursorString != nil {
+		req.SetQueryParam("cursor_string", *r.CursorString)
+	}
+
+	resp, err := req.Get("events")
+	if err != nil {
+		return nil, err
+	}
+
+	raw := resp.Result().(*eventsResponse)
+
+	events, err := decodeEvents(raw.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventsResponse{Events: events, CursorString: raw.CursorString}, nil
+}
+
+// ErrMissedEvents is returned by EventPoller.Poll alongside the events it fetched when more events arrived
+// since the previous poll than MaxPages pages hold, so older ones were skipped.
+var ErrMissedEvents = errors.New("missed events")
+
+// EventPoller polls the global events feed and returns only the events it has not seen before.
+type EventPoller struct {
+	client   *Client
+	Interval time.Duration
+	// LastID is the ID of the newest event seen. Set it to resume from a previous run;
+	// when it is zero the first poll only records the current position.
+	LastID int
+	// MaxPages limits how many pages a single poll walks back to catch up.
+	MaxPages int
+	// OnError receives poll errors, including ErrMissedEvents, and keeps Run going. Without it, Run returns them.
+	OnError func(err error)
+}
+
+// NewEventPoller creates a poller for the global events feed.
+func (c *Client) NewEventPoller(interval time.Duration) *EventPoller {
+	return &EventPoller{client: c, Interval: interval, MaxPages: 5}
+}
+
+// Poll fetches the events newer than LastID, oldest first.
+func (p *EventPoller) Poll() ([]Event, error) {
+	var result []Event
+	var cursor *string
+	caughtUp := false
+
+	for page := 0; page < max(p.MaxPages, 1) && !caughtUp; page++ {
+		req := p.client.GetEvents().SetSort(SortDescending)
+		if cursor != nil {
+			req.SetCursorString(*cursor)
+		}
+
+		resp, err := req.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		caughtUp = p.LastID == 0 || resp.CursorString == nil
+		for _, event := range resp.Events {
+			if event.Base().ID <= p.LastID {
+				caughtUp = true
+				break
+			}
+			result = append(result, event)
+		}
+
+		cursor = resp.CursorString
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	first := p.LastID == 0
+	p.LastID = result[0].Base().ID
+
+	if first {
+		return nil, nil
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	if !caughtUp {
+		return result, ErrMissedEvents
+	}
+
+	return result, nil
+}
+
+// Run polls every Interval and sends new events on the channel until the context is cancelled.
+func (p *EventPoller) Run(ctx context.Context, events chan<- Event) error {
+	return runPollLoop(ctx, p.Interval, events, func() ([]Event, error) {
+		newEvents, err := p.Poll()
+		if err != nil && p.OnError != nil {
+			p.OnError(err)
+			err = nil
+		}
+		return newEvents, err
+	})
+}
@@ -4,7 +4,7 @@ go 1.22
 
 require (
 	github.com/go-resty/resty/v2 v2.13.1
-	github.com/mitchellh/mapstructure v1.5.0
+	github.com/ulikunitz/xz v0.5.12
 	golang.org/x/oauth2 v0.21.0
 )
 
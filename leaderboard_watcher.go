@@ -0,0 +1,235 @@
+package gosu
+
+import (
+	"context"
+	"time"
+)
+
+// LeaderboardKey identifies the leaderboard of a beatmap in a ruleset.
+type LeaderboardKey struct {
+	Beatmap int
+	Mode    Ruleset
+}
+
+// LeaderboardEvent is implemented by the events emitted by a LeaderboardWatcher.
+type LeaderboardEvent interface {
+	Leaderboard() LeaderboardKey
+}
+
+// FirstPlaceEvent is emitted when the #1 score on a leaderboard changes, including when its holder improves it.
+type FirstPlaceEvent struct {
+	Key   LeaderboardKey
+	Score Score
+	// Previous is the entry that held #1 before, or nil if the leaderboard was empty.
+	Previous *LeaderboardEntry
+}
+
+func (e FirstPlaceEvent) Leaderboard() LeaderboardKey {
+	return e.Key
+}
+
+// RankChangeEvent is emitted when a user enters the watched leaderboard or their position on it changes.
+type RankChangeEvent struct {
+	Key   LeaderboardKey
+	User  int
+	Score Score
+	// From is the previous position of the user starting at 1, or nil if they were not on the leaderboard.
+	From *int
+	To   int
+}
+
+func (e RankChangeEvent) Leaderboard() LeaderboardKey {
+	return e.Key
+}
+
+// DisplacedEvent is emitted when a user is pushed out of the watched leaderboard.
+type DisplacedEvent struct {
+	Key      LeaderboardKey
+	User     int
+	Previous LeaderboardEntry
+}
+
+func (e DisplacedEvent) Leaderboard() LeaderboardKey {
+	return e.Key
+}
+
+type LeaderboardEntry struct {
+	Position int
+	User     int
+	ScoreID  int
+	Score    int
+	PP       float32
+}
+
+// LeaderboardState is what a LeaderboardWatcher remembers about a leaderboard between polls.
+type LeaderboardState struct {
+	Entries    []LeaderboardEntry
+	LastPolled time.Time
+}
+
+// LeaderboardStore persists the state of watched leaderboards.
+type LeaderboardStore interface {
+	// Load returns the state of a leaderboard, or nil if it has never been polled.
+	Load(key LeaderboardKey) (*LeaderboardState, error)
+	Save(key LeaderboardKey, state *LeaderboardState) error
+}
+
+// MemoryLeaderboardStore keeps leaderboard state in memory.
+type MemoryLeaderboardStore struct {
+	memoryStore[LeaderboardKey, LeaderboardState]
+}
+
+func NewMemoryLeaderboardStore() *MemoryLeaderboardStore {
+	return &MemoryLeaderboardStore{}
+}
+
+// LeaderboardWatcher polls the top scores of a set of beatmaps and emits changes to their leaderboards.
+type LeaderboardWatcher struct {
+	client *Client
+	Store  LeaderboardStore
+	// Size is the number of leaderboard positions watched, starting from #1. It can be at most 100.
+	Size int
+	// RequestsPerMinute is the request budget of the watcher. Every beatmap poll costs one request.
+	RequestsPerMinute int
+	// OnError handles a failed leaderboard poll, which is retried on its next turn. Without it, Run returns the error.
+	OnError func(key LeaderboardKey, err error)
+
+	leaderboards roundRobin[LeaderboardKey]
+}
+
+// NewLeaderboardWatcher creates a watcher for the top 50 of the beatmaps added with AddBeatmap.
+// Their state is kept in memory if store is nil.
+func (c *Client) NewLeaderboardWatcher(store LeaderboardStore) *LeaderboardWatcher {
+	if store == nil {
+		store = NewMemoryLeaderboardStore()
+	}
+
+	return &LeaderboardWatcher{client: c, Store: store, Size: 50, RequestsPerMinute: 60}
+}
+
+func (w *LeaderboardWatcher) AddBeatmap(beatmap int, mode Ruleset) {
+	w.leaderboards.add(LeaderboardKey{Beatmap: beatmap, Mode: mode})
+}
+
+func (w *LeaderboardWatcher) RemoveBeatmap(beatmap int, mode Ruleset) {
+	w.leaderboards.remove(LeaderboardKey{Beatmap: beatmap, Mode: mode})
+}
+
+func (w *LeaderboardWatcher) Leaderboards() []LeaderboardKey {
+	return w.leaderboards.list()
+}
+
+// PollLeaderboard fetches a leaderboard and returns the changes since the previous poll.
+// The first poll of a leaderboard only records its state.
+func (w *LeaderboardWatcher) PollLeaderboard(key LeaderboardKey) ([]LeaderboardEvent, error) {
+	req := w.client.GetBeatmapScores(key.Beatmap).SetMode(key.Mode)
+	if w.Size > 0 {
+		req.SetLimit(w.Size)
+	}
+
+	resp, err := req.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var scores []Score
+	for _, score := range resp.Scores {
+		if w.Size > 0 && len(scores) >= w.Size {
+			break
+		}
+		scores = append(scores, score.Score)
+	}
+
+	previous, err := w.Store.Load(key)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &LeaderboardState{LastPolled: time.Now()}
+	for i, score := range scores {
+		state.Entries = append(state.Entries, LeaderboardEntry{
+			Position: i + 1,
+			User:     score.UserID,
+			ScoreID:  score.ID,
+			Score:    score.Score,
+			PP:       score.Pp,
+		})
+	}
+
+	var events []LeaderboardEvent
+
+	if previous != nil {
+		events = diffLeaderboard(key, previous.Entries, state.Entries, scores)
+	}
+
+	if err := w.Store.Save(key, state); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func diffLeaderboard(key LeaderboardKey, previous, current []LeaderboardEntry, scores []Score) []LeaderboardEvent {
+	var events []LeaderboardEvent
+
+	if len(current) > 0 && (len(previous) == 0 || previous[0].ScoreID != current[0].ScoreID) {
+		event := FirstPlaceEvent{Key: key, Score: scores[0]}
+		if len(previous) > 0 {
+			event.Previous = &previous[0]
+		}
+		events = append(events, event)
+	}
+
+	positions := make(map[int]int, len(previous))
+	for _, entry := range previous {
+		positions[entry.User] = entry.Position
+	}
+
+	remaining := make(map[int]bool, len(current))
+	for i, entry := range current {
+		remaining[entry.User] = true
+
+		from, ok := positions[entry.User]
+		if ok && from == entry.Position {
+			continue
+		}
+
+		event := RankChangeEvent{Key: key, User: entry.User, Score: scores[i], To: entry.Position}
+		if ok {
+			event.From = &from
+		}
+		events = append(events, event)
+	}
+
+	for _, entry := range previous {
+		if !remaining[entry.User] {
+			events = append(events, DisplacedEvent{Key: key, User: entry.User, Previous: entry})
+		}
+	}
+
+	return events
+}
+
+// Run polls the watched leaderboards one at a time, spacing requests to stay within RequestsPerMinute,
+// and sends events on the channel until the context is cancelled.
+func (w *LeaderboardWatcher) Run(ctx context.Context, events chan<- LeaderboardEvent) error {
+	requestsPerMinute := w.RequestsPerMinute
+	if requestsPerMinute <= 0 {
+		requestsPerMinute = 60
+	}
+
+	return runPollLoop(ctx, time.Minute/time.Duration(requestsPerMinute), events, func() ([]LeaderboardEvent, error) {
+		key, ok := w.leaderboards.nextKey()
+		if !ok {
+			return nil, nil
+		}
+
+		newEvents, err := w.PollLeaderboard(key)
+		if err != nil && w.OnError != nil {
+			w.OnError(key, err)
+			err = nil
+		}
+
+		return newEvents, err
+	})
+}
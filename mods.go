@@ -1,6 +1,12 @@
 package gosu
 
-import "strings"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
 
 type Mod int
 
@@ -72,6 +78,24 @@ var modStrings = map[Mod]string{
 	MR:  "MR",
 }
 
+// modValues maps the legacy acronyms, plus the lazer ones that do not clash with them such as 4K for K4, for parsing
+// user input. TR is legacy Target Practice here, while in lazer data it is Transform, so lazer acronyms are looked up
+// with APIMod.Legacy instead.
+var modValues = func() map[string]Mod {
+	values := make(map[string]Mod, len(modStrings)+len(legacyAPIModAcronyms)+2)
+	for mod, acronym := range modStrings {
+		values[acronym] = mod
+	}
+	for mod, acronym := range legacyAPIModAcronyms {
+		values[acronym] = mod
+	}
+	values["NM"] = 0
+	values["V2"] = SV2
+	return values
+}()
+
+var ErrInvalidMod = errors.New("not a valid Mod")
+
 func ModsToString(mods Mod) string {
 	var result strings.Builder
 
@@ -95,3 +119,419 @@ func ModsToStrings(mods Mod) []string {
 
 	return result
 }
+
+// ParseMods converts a string of concatenated acronyms such as "HDDTHR" to a Mod.
+// Acronyms are case-insensitive and may be separated by spaces, commas or plus signs.
+func ParseMods(mods string) (Mod, error) {
+	var result Mod
+
+	mods = strings.ToUpper(mods)
+	for len(mods) > 0 {
+		switch mods[0] {
+		case ' ', ',', '+':
+			mods = mods[1:]
+			continue
+		}
+
+		if len(mods) >= 3 && mods[:3] == "SV2" {
+			result |= SV2
+			mods = mods[3:]
+			continue
+		}
+
+		if len(mods) < 2 {
+			return 0, fmt.Errorf("%s is %w", mods, ErrInvalidMod)
+		}
+
+		mod, ok := modValues[mods[:2]]
+		if !ok {
+			return 0, fmt.Errorf("%s is %w", mods[:2], ErrInvalidMod)
+		}
+
+		result |= mod
+		mods = mods[2:]
+	}
+
+	return result, nil
+}
+
+// ModsFromStrings converts a list of legacy acronyms such as the one returned by ModsToStrings to a Mod.
+// Use Score.LegacyMods for the lazer acronyms of Score.Mods.
+func ModsFromStrings(mods []string) (Mod, error) {
+	var result Mod
+
+	for _, acronym := range mods {
+		mod, ok := modValues[strings.ToUpper(acronym)]
+		if !ok {
+			return 0, fmt.Errorf("%s is %w", acronym, ErrInvalidMod)
+		}
+		result |= mod
+	}
+
+	return result, nil
+}
+
+// MarshalJSON encodes the mods as a bitmask.
+func (m Mod) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int(m))
+}
+
+// UnmarshalJSON accepts a bitmask, a string of legacy acronyms, or an array of lazer acronyms or mod objects.
+// Lazer acronyms that have no legacy bit, such as lazer-only mods, are ignored.
+func (m *Mod) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	var result Mod
+
+	switch v := value.(type) {
+	case nil:
+	case float64:
+		if v < 0 || v != math.Trunc(v) {
+			return fmt.Errorf("%s is %w", data, ErrInvalidMod)
+		}
+		result = Mod(v)
+	case string:
+		mods, err := ParseMods(v)
+		if err != nil {
+			return err
+		}
+		result = mods
+	case []interface{}:
+		for _, item := range v {
+			switch mod := item.(type) {
+			case string:
+				result |= legacyModFromAPIAcronym(mod)
+			case map[string]interface{}:
+				if acronym, ok := mod["acronym"].(string); ok {
+					result |= legacyModFromAPIAcronym(acronym)
+				}
+			default:
+				return fmt.Errorf("%v is %w", item, ErrInvalidMod)
+			}
+		}
+	default:
+		return fmt.Errorf("%s is %w", data, ErrInvalidMod)
+	}
+
+	*m = result
+	return nil
+}
+
+// legacyModFromAPIAcronym returns the legacy bit of a whole lazer acronym, or 0 if it has none.
+func legacyModFromAPIAcronym(acronym string) Mod {
+	mod, _ := APIMod{Acronym: strings.ToUpper(acronym)}.Legacy()
+	return mod
+}
+
+// KeyMods are the mania key count mods, of which only one can be enabled.
+const KeyMods = K1 | K2 | K3 | K4 | K5 | K6 | K7 | K8 | K9
+
+// DifficultyAffectingMods are the mods that change difficulty attributes.
+const DifficultyAffectingMods = EZ | TD | HR | DT | HT | FL | KeyMods
+
+var rulesetMods = map[Ruleset]Mod{
+	RulesetOsu:    NF | EZ | TD | HD | HR | SD | DT | RX | HT | NC | FL | AT | SO | AP | PF | CN | TR | SV2 | MR,
+	RulesetTaiko:  NF | EZ | HD | HR | SD | DT | RX | HT | NC | FL | AT | PF | CN | RN | SV2,
+	RulesetFruits: NF | EZ | HD | HR | SD | DT | RX | HT | NC | FL | AT | PF | CN | SV2,
+	RulesetMania:  NF | EZ | HD | HR | SD | DT | HT | NC | FL | AT | PF | CN | FI | RN | SV2 | MR | KC | KeyMods,
+}
+
+// incompatibleMods lists pairs of mods that cannot be enabled together.
+var incompatibleMods = [][2]Mod{
+	{EZ, HR},
+	{DT, HT},
+	{NC, HT},
+	{HD, FI},
+	{NF, SD},
+	{NF, PF},
+	{NF, RX},
+	{NF, AP},
+	{SD, RX},
+	{SD, AP},
+	{SD, AT},
+	{PF, RX},
+	{PF, AP},
+	{PF, AT},
+	{RX, AP},
+	{RX, AT},
+	{AP, AT},
+	{AP, SO},
+	{AT, SO},
+	{CN, AT},
+	{CN, RX},
+	{CN, AP},
+}
+
+var (
+	ErrIncompatibleMods = errors.New("incompatible mods")
+	ErrModNotAllowed    = errors.New("mod not allowed for ruleset")
+)
+
+// ModValidationError describes why a Mod combination is invalid.
+type ModValidationError struct {
+	// Incompatible holds each combination of mods that cannot be enabled together.
+	Incompatible []Mod
+	// NotAllowed holds the mods that do not exist in the ruleset.
+	NotAllowed Mod
+	Ruleset    *Ruleset
+}
+
+func (e *ModValidationError) Error() string {
+	var reasons []string
+
+	for _, mods := range e.Incompatible {
+		reasons = append(reasons, fmt.Sprintf("%s are %v", ModsToString(mods), ErrIncompatibleMods))
+	}
+
+	if e.NotAllowed != 0 && e.Ruleset != nil {
+		reasons = append(reasons, fmt.Sprintf("%s %v %s", ModsToString(e.NotAllowed), ErrModNotAllowed, e.Ruleset.String()))
+	}
+
+	return strings.Join(reasons, "; ")
+}
+
+func (e *ModValidationError) Unwrap() []error {
+	var errs []error
+
+	if len(e.Incompatible) > 0 {
+		errs = append(errs, ErrIncompatibleMods)
+	}
+
+	if e.NotAllowed != 0 {
+		errs = append(errs, ErrModNotAllowed)
+	}
+
+	return errs
+}
+
+// Validate returns a *ModValidationError if the mods contain incompatible combinations or mods that do not exist in the ruleset.
+func (m Mod) Validate(ruleset Ruleset) error {
+	err := m.validateCombinations()
+	if err == nil {
+		err = &ModValidationError{}
+	}
+
+	if allowed, ok := rulesetMods[ruleset]; ok {
+		err.NotAllowed = m &^ allowed
+		err.Ruleset = &ruleset
+	}
+
+	if len(err.Incompatible) == 0 && err.NotAllowed == 0 {
+		return nil
+	}
+
+	return err
+}
+
+// validateCombinations checks the mods against each other, regardless of ruleset.
+func (m Mod) validateCombinations() *ModValidationError {
+	var incompatible []Mod
+
+	for _, pair := range incompatibleMods {
+		if m&pair[0] != 0 && m&pair[1] != 0 {
+			incompatible = append(incompatible, pair[0]|pair[1])
+		}
+	}
+
+	if keys := m & KeyMods; keys&(keys-1) != 0 {
+		incompatible = append(incompatible, keys)
+	}
+
+	if len(incompatible) == 0 {
+		return nil
+	}
+
+	return &ModValidationError{Incompatible: incompatible}
+}
+
+// Normalize returns the mods in the form used by the game, where NC implies DT and PF implies SD.
+func (m Mod) Normalize() Mod {
+	if m&NC != 0 {
+		m |= DT
+	}
+
+	if m&PF != 0 {
+		m |= SD
+	}
+
+	return m
+}
+
+// DifficultyAffecting returns only the mods that change difficulty attributes, suitable as a cache key.
+func (m Mod) DifficultyAffecting() Mod {
+	if m&NC != 0 {
+		m |= DT
+	}
+
+	return m & DifficultyAffectingMods
+}
+
+// legacyScoreMultipliers are the stable score multipliers of each mod per ruleset. Mods not listed have a multiplier of 1.
+var legacyScoreMultipliers = map[Ruleset]map[Mod]float64{
+	RulesetOsu:    {NF: 0.5, EZ: 0.5, HT: 0.3, HD: 1.06, HR: 1.06, DT: 1.12, FL: 1.12, SO: 0.9, RX: 0, AP: 0},
+	RulesetTaiko:  {NF: 0.5, EZ: 0.5, HT: 0.3, HD: 1.06, HR: 1.06, DT: 1.12, FL: 1.12, RX: 0},
+	RulesetFruits: {NF: 0.5, EZ: 0.5, HT: 0.3, HD: 1.06, HR: 1.12, DT: 1.06, FL: 1.12, RX: 0},
+	RulesetMania:  {NF: 0.5, EZ: 0.5, HT: 0.5},
+}
+
+// lazerScoreMultipliers are the lazer score multipliers of each mod per ruleset, using default mod settings.
+// Rate mods are derived from the rate by lazerRateScoreMultiplier, except where a ruleset fixes them, as osu!catch
+// does for DT.
+var lazerScoreMultipliers = map[Ruleset]map[Mod]float64{
+	RulesetOsu:    {NF: 0.5, EZ: 0.5, HD: 1.06, HR: 1.06, FL: 1.12, SO: 0.9, RX: 0.1, AP: 0.1},
+	RulesetTaiko:  {NF: 0.5, EZ: 0.5, HD: 1.06, HR: 1.06, FL: 1.12, RX: 0.1},
+	RulesetFruits: {NF: 0.5, EZ: 0.5, HD: 1.06, HR: 1.12, DT: 1.06, FL: 1.12, RX: 0.1},
+	RulesetMania:  {NF: 0.5, EZ: 0.5, K1: 0.9, K2: 0.9, K3: 0.9, K4: 0.9, K5: 0.9, K6: 0.9, K7: 0.9, K8: 0.9, K9: 0.9},
+}
+
+// lazerRateScoreMultiplier returns the lazer score multiplier of a rate mod, which rounds the rate down to a
+// multiple of 0.1 and gives 0.02 per 0.1 above 1 and takes 0.1 per 0.1 below it.
+func lazerRateScoreMultiplier(rate float64) float64 {
+	value := math.Floor(rate*10)/10 - 1
+
+	if value > 0 {
+		return 1 + value/5
+	}
+
+	return 1 + value
+}
+
+func scoreMultiplier(mods Mod, multipliers map[Mod]float64) float64 {
+	// NC and PF share the multiplier of the mod they imply.
+	if mods&NC != 0 {
+		mods = mods&^NC | DT
+	}
+
+	if mods&PF != 0 {
+		mods = mods&^PF | SD
+	}
+
+	multiplier := 1.0
+
+	for mod, value := range multipliers {
+		if mods&mod != 0 {
+			multiplier *= value
+		}
+	}
+
+	return multiplier
+}
+
+// ScoreMultiplier returns the stable score multiplier of the mods in a ruleset.
+func (m Mod) ScoreMultiplier(ruleset Ruleset) float64 {
+	return scoreMultiplier(m, legacyScoreMultipliers[ruleset])
+}
+
+// LazerScoreMultiplier returns the lazer score multiplier of the mods in a ruleset, assuming default mod settings,
+// so 1.5x for DT and NC and 0.75x for HT.
+func (m Mod) LazerScoreMultiplier(ruleset Ruleset) float64 {
+	return m.LazerScoreMultiplierAtRate(ruleset, m.ClockRate())
+}
+
+// LazerScoreMultiplierAtRate returns the lazer score multiplier of the mods in a ruleset with DT, NC or HT set to a
+// custom rate, such as the one returned by APIModsClockRate.
+func (m Mod) LazerScoreMultiplierAtRate(ruleset Ruleset, rate float64) float64 {
+	multipliers := lazerScoreMultipliers[ruleset]
+	multiplier := scoreMultiplier(m&^(DT|NC|HT), multipliers)
+
+	rateMod := m & (DT | NC | HT)
+	if rateMod&NC != 0 {
+		rateMod = DT
+	}
+
+	if rateMod == 0 {
+		return multiplier
+	}
+
+	if fixed, ok := multipliers[rateMod]; ok {
+		return multiplier * fixed
+	}
+
+	return multiplier * lazerRateScoreMultiplier(rate)
+}
+
+// ClockRate returns the playback speed of the mods: 1.5 for DT and NC, 0.75 for HT.
+func (m Mod) ClockRate() float64 {
+	switch {
+	case m&(DT|NC) != 0:
+		return 1.5
+	case m&HT != 0:
+		return 0.75
+	}
+
+	return 1
+}
+
+// difficultyMultiplier returns the multiplier HR or EZ apply to a difficulty setting.
+func (m Mod) difficultyMultiplier(hardRock float64) float64 {
+	switch {
+	case m&HR != 0:
+		return hardRock
+	case m&EZ != 0:
+		return 0.5
+	}
+
+	return 1
+}
+
+// ApproachRate returns the effective approach rate after HR, EZ and rate changes.
+func (m Mod) ApproachRate(ar float64) float64 {
+	ar = math.Min(ar*m.difficultyMultiplier(1.4), 10)
+
+	rate := m.ClockRate()
+	if rate == 1 {
+		return ar
+	}
+
+	var preempt float64
+	if ar < 5 {
+		preempt = 1200 + 600*(5-ar)/5
+	} else {
+		preempt = 1200 - 750*(ar-5)/5
+	}
+
+	preempt /= rate
+
+	if preempt > 1200 {
+		return 5 - (preempt-1200)*5/600
+	}
+
+	return 5 + (1200-preempt)*5/750
+}
+
+// OverallDifficulty returns the effective overall difficulty after HR, EZ and rate changes.
+// Rate changes only affect the displayed overall difficulty in osu! and osu!taiko.
+func (m Mod) OverallDifficulty(od float64, ruleset Ruleset) float64 {
+	od = math.Min(od*m.difficultyMultiplier(1.4), 10)
+
+	rate := m.ClockRate()
+	if rate == 1 {
+		return od
+	}
+
+	switch ruleset {
+	case RulesetOsu:
+		return (80 - (80-6*od)/rate) / 6
+	case RulesetTaiko:
+		return (50 - (50-3*od)/rate) / 3
+	}
+
+	return od
+}
+
+// CircleSize returns the effective circle size after HR and EZ. In osu!mania it is the key count and is not affected.
+func (m Mod) CircleSize(cs float64, ruleset Ruleset) float64 {
+	if ruleset == RulesetMania {
+		return cs
+	}
+
+	return math.Min(cs*m.difficultyMultiplier(1.3), 10)
+}
+
+// DrainRate returns the effective HP drain rate after HR and EZ.
+func (m Mod) DrainRate(hp float64) float64 {
+	return math.Min(hp*m.difficultyMultiplier(1.4), 10)
+}
@@ -0,0 +1,219 @@
+package gosu
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseMods(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Mod
+		err   error
+	}{
+		{"", 0, nil},
+		{"NM", 0, nil},
+		{"HDDTHR", HD | DT | HR, nil},
+		{"hd,dt", HD | DT, nil},
+		{"HD+HR FL", HD | HR | FL, nil},
+		{"SV2", SV2, nil},
+		{"V2", SV2, nil},
+		{"K4", K4, nil},
+		{"4K", K4, nil},
+		{"1K", K1, nil},
+		{"RD", RN, nil},
+		{"RN", RN, nil},
+		{"DS", KC, nil},
+		{"TP", TR, nil},
+		{"HD4KDS", HD | K4 | KC, nil},
+		{"XX", 0, ErrInvalidMod},
+		{"HDD", 0, ErrInvalidMod},
+		{"HDXX", 0, ErrInvalidMod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMods(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ParseMods(%q) error = %v, want %v", tt.input, err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMods(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModsFromStrings(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  Mod
+		err   error
+	}{
+		{"legacy", []string{"HD", "DT"}, HD | DT, nil},
+		{"lazer key count", []string{"4K"}, K4, nil},
+		{"lazer acronyms", []string{"RD", "DS", "TP"}, RN | KC | TR, nil},
+		{"lowercase", []string{"nc"}, NC, nil},
+		{"legacy target practice", []string{"TR"}, TR, nil},
+		{"lazer only", []string{"HD", "CL"}, 0, ErrInvalidMod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ModsFromStrings(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ModsFromStrings(%v) error = %v, want %v", tt.input, err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("ModsFromStrings(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreLegacyMods(t *testing.T) {
+	tests := []struct {
+		name        string
+		mods        []string
+		want        Mod
+		wantUnknown []string
+	}{
+		{"none", nil, 0, nil},
+		{"legacy", []string{"HD", "HR"}, HD | HR, nil},
+		{"lazer acronyms", []string{"7K", "DS"}, K7 | KC, nil},
+		{"lazer only", []string{"DT", "CL", "DA"}, DT, []string{"CL", "DA"}},
+		{"target practice", []string{"TP"}, TR, nil},
+		{"transform", []string{"HD", "TR"}, HD, []string{"TR"}},
+		{"nightcore", []string{"NC"}, NC | DT, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unknown := Score{Mods: tt.mods}.LegacyMods()
+			if got != tt.want {
+				t.Errorf("LegacyMods() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(unknown, tt.wantUnknown) {
+				t.Errorf("LegacyMods() unknown = %v, want %v", unknown, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestModUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Mod
+	}{
+		{"bitmask", `72`, HD | DT},
+		{"acronym string", `"HDHR"`, HD | HR},
+		{"acronym array", `["HD", "4K"]`, HD | K4},
+		{"mod objects", `[{"acronym": "DT", "settings": {"speed_change": 1.3}}, {"acronym": "RD"}]`, DT | RN},
+		{"lazer only acronyms", `["CL", "HD"]`, HD},
+		{"legacy target practice string", `"TR"`, TR},
+		{"target practice object", `[{"acronym": "TP"}]`, TR},
+		{"transform object", `[{"acronym": "TR"}]`, 0},
+		{"transform acronym", `["TR", "HD"]`, HD},
+		{"acronyms are not split", `[{"acronym": "HDDT"}]`, 0},
+		{"null", `null`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Mod
+			if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+				t.Fatalf("Unmarshal(%s) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModUnmarshalJSONInvalid(t *testing.T) {
+	tests := []string{`-8`, `8.5`, `"HDXX"`, `[1]`, `true`}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			var got Mod
+			if err := json.Unmarshal([]byte(input), &got); !errors.Is(err, ErrInvalidMod) {
+				t.Errorf("Unmarshal(%s) error = %v, want %v", input, err, ErrInvalidMod)
+			}
+		})
+	}
+}
+
+func TestModsAPIRoundTrip(t *testing.T) {
+	tests := []Mod{
+		HD | DT,
+		K4 | KC,
+		RN | MR,
+		TR,
+	}
+
+	for _, mods := range tests {
+		t.Run(ModsToString(mods), func(t *testing.T) {
+			got, remaining := ModsFromAPIMods(mods.APIMods())
+			if got != mods || remaining != nil {
+				t.Errorf("ModsFromAPIMods(%v.APIMods()) = %v, %v, want %v", mods, got, remaining, mods)
+			}
+		})
+	}
+}
+
+func TestScoreMultiplier(t *testing.T) {
+	tests := []struct {
+		name    string
+		mods    Mod
+		ruleset Ruleset
+		legacy  float64
+		lazer   float64
+	}{
+		{"no mods", 0, RulesetOsu, 1, 1},
+		{"hidden double time", HD | DT, RulesetOsu, 1.06 * 1.12, 1.06 * 1.1},
+		{"nightcore", NC, RulesetOsu, 1.12, 1.1},
+		{"half time", HT, RulesetTaiko, 0.3, 0.7},
+		{"catch double time", HD | DT, RulesetFruits, 1.06 * 1.06, 1.06 * 1.06},
+		{"catch hard rock", HR, RulesetFruits, 1.12, 1.12},
+		{"perfect", PF | HR, RulesetOsu, 1.06, 1.06},
+		{"relax", RX, RulesetOsu, 0, 0.1},
+		{"mania key mod", K4 | HT, RulesetMania, 0.5, 0.9 * 0.7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mods.ScoreMultiplier(tt.ruleset); !withinTolerance(got, tt.legacy) {
+				t.Errorf("ScoreMultiplier() = %v, want %v", got, tt.legacy)
+			}
+			if got := tt.mods.LazerScoreMultiplier(tt.ruleset); !withinTolerance(got, tt.lazer) {
+				t.Errorf("LazerScoreMultiplier() = %v, want %v", got, tt.lazer)
+			}
+		})
+	}
+}
+
+func TestLazerScoreMultiplierAtRate(t *testing.T) {
+	tests := []struct {
+		mods Mod
+		rate float64
+		want float64
+	}{
+		{DT, 1.01, 1},
+		{DT, 1.25, 1.04},
+		{DT, 2, 1.2},
+		{HT, 0.5, 0.5},
+		{HT, 0.99, 0.9},
+		{HD | NC, 1.3, 1.06 * 1.06},
+	}
+
+	for _, tt := range tests {
+		if got := tt.mods.LazerScoreMultiplierAtRate(RulesetOsu, tt.rate); !withinTolerance(got, tt.want) {
+			t.Errorf("%v.LazerScoreMultiplierAtRate(%v) = %v, want %v", tt.mods, tt.rate, got, tt.want)
+		}
+	}
+}
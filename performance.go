@@ -0,0 +1,699 @@
+package gosu
+
+import "math"
+
+// PerformanceBeatmap is the beatmap data needed to calculate performance points.
+type PerformanceBeatmap struct {
+	StarRating    float64
+	MaxCombo      int
+	CountCircles  int
+	CountSliders  int
+	CountSpinners int
+	// Convert is whether the beatmap is played in a ruleset other than its own.
+	Convert bool
+}
+
+// NewPerformanceBeatmap combines a beatmap's object counts with difficulty attributes returned by GetBeatmapAttributes.
+func NewPerformanceBeatmap(beatmap *Beatmap, attributes *DifficultyAttributes) PerformanceBeatmap {
+	return PerformanceBeatmap{
+		StarRating:    float64(attributes.StarRating),
+		MaxCombo:      attributes.MaxCombo,
+		CountCircles:  beatmap.CountCircles,
+		CountSliders:  beatmap.CountSliders,
+		CountSpinners: beatmap.CountSpinners,
+		Convert:       attributes.Mode != nil && *attributes.Mode != beatmap.Mode,
+	}
+}
+
+// Performance returns the performance points of a play on the beatmap the attributes were calculated for.
+func (a *DifficultyAttributes) Performance(beatmap *Beatmap, score PerformanceScore) PerformanceAttributes {
+	performanceBeatmap := NewPerformanceBeatmap(beatmap, a)
+
+	switch {
+	case a.Osu != nil:
+		return CalculateOsuPerformance(performanceBeatmap, *a.Osu, score)
+	case a.Taiko != nil:
+		return CalculateTaikoPerformance(performanceBeatmap, *a.Taiko, score)
+	case a.Fruits != nil:
+		return CalculateFruitsPerformance(performanceBeatmap, *a.Fruits, score)
+	case a.Mania != nil:
+		return CalculateManiaPerformance(performanceBeatmap, *a.Mania, score)
+	}
+
+	return PerformanceAttributes{}
+}
+
+// PerformanceScore describes a play, real or hypothetical.
+// The difficulty attributes passed alongside it must already be calculated with the same mods.
+type PerformanceScore struct {
+	Mods Mod
+	// Combo defaults to the beatmap's max combo.
+	Combo *int
+	// Accuracy between 0 and 1, used to estimate hit counts when Statistics is nil. Defaults to 1.
+	Accuracy *float64
+	Misses   int
+	// Statistics are the exact hit counts of a score and take precedence over Accuracy and Misses.
+	Statistics *ScoreStatistics
+}
+
+type PerformanceAttributes struct {
+	PP                 float64
+	Aim                float64
+	Speed              float64
+	Accuracy           float64
+	Flashlight         float64
+	Difficulty         float64
+	EffectiveMissCount float64
+}
+
+func (s PerformanceScore) combo(maxCombo int) int {
+	if s.Combo != nil {
+		return *s.Combo
+	}
+	return maxCombo
+}
+
+func (s PerformanceScore) accuracy() float64 {
+	if s.Accuracy != nil {
+		return math.Max(0, math.Min(1, *s.Accuracy))
+	}
+	return 1
+}
+
+func clampCount(count float64, max int) int {
+	return int(math.Round(math.Max(0, math.Min(float64(max), count))))
+}
+
+// osuStatistics estimates great/ok/meh counts from accuracy when the score has no hit counts.
+func (s PerformanceScore) osuStatistics(totalHits int) ScoreStatistics {
+	if s.Statistics != nil {
+		return *s.Statistics
+	}
+
+	misses := min(s.Misses, totalHits)
+	remaining := totalHits - misses
+	target := s.accuracy() * float64(totalHits) * 6
+
+	// Prefer oks over mehs, falling back to mehs when the accuracy is too low for greats and oks alone.
+	count100 := clampCount((float64(remaining)*6-target)/4, remaining)
+	count300 := remaining - count100
+	count50 := 0
+
+	if target < float64(remaining)*2 {
+		count300 = 0
+		count100 = clampCount(target-float64(remaining), remaining)
+		count50 = remaining - count100
+	}
+
+	return ScoreStatistics{Count300: count300, Count100: count100, Count50: count50, CountMiss: misses}
+}
+
+func osuAccuracy(statistics ScoreStatistics) float64 {
+	totalHits := statistics.Count300 + statistics.Count100 + statistics.Count50 + statistics.CountMiss
+	if totalHits == 0 {
+		return 0
+	}
+
+	return float64(statistics.Count300*6+statistics.Count100*2+statistics.Count50) / float64(totalHits*6)
+}
+
+// CalculateOsuPerformance returns the performance points of an osu! play using the formula of lazer's
+// OsuPerformanceCalculator, which penalises speed by the tapping deviation estimated from the hit counts.
+// Scores are treated like stable scores, since ScoreStatistics has no slider tail or tick counts.
+func CalculateOsuPerformance(beatmap PerformanceBeatmap, attributes OsuDifficultyAttributes, score PerformanceScore) PerformanceAttributes {
+	totalHits := beatmap.CountCircles + beatmap.CountSliders + beatmap.CountSpinners
+	statistics := score.osuStatistics(totalHits)
+	if score.Statistics != nil {
+		totalHits = statistics.Count300 + statistics.Count100 + statistics.Count50 + statistics.CountMiss
+	}
+
+	if totalHits == 0 {
+		return PerformanceAttributes{}
+	}
+
+	c := osuPerformance{
+		beatmap:    beatmap,
+		attributes: attributes,
+		mods:       score.Mods,
+		combo:      float64(score.combo(beatmap.MaxCombo)),
+		statistics: statistics,
+		totalHits:  float64(totalHits),
+		accuracy:   osuAccuracy(statistics),
+		ar:         float64(attributes.ApproachRate),
+		od:         float64(attributes.OverallDifficulty),
+	}
+
+	c.calculateHitWindows()
+	c.effectiveMissCount = c.calculateEffectiveMissCount()
+
+	multiplier := 1.15
+
+	if c.mods&NF != 0 {
+		multiplier *= math.Max(0.90, 1-0.02*c.effectiveMissCount)
+	}
+
+	if c.mods&SO != 0 {
+		multiplier *= 1 - math.Pow(float64(beatmap.CountSpinners)/c.totalHits, 0.85)
+	}
+
+	if c.mods&RX != 0 {
+		// Relax hides oks and mehs as misses since they are mostly caused by tapping too early.
+		okMultiplier, mehMultiplier := 1.0, 1.0
+		if c.od > 0 {
+			okMultiplier = math.Max(0, 1-math.Pow(c.od/13.33, 1.8))
+			mehMultiplier = math.Max(0, 1-math.Pow(c.od/13.33, 5))
+		}
+
+		c.effectiveMissCount = math.Min(c.effectiveMissCount+float64(statistics.Count100)*okMultiplier+float64(statistics.Count50)*mehMultiplier, c.totalHits)
+	}
+
+	c.speedDeviation, c.hasSpeedDeviation = c.calculateSpeedDeviation()
+
+	result := PerformanceAttributes{
+		Aim:                c.aim(),
+		Speed:              c.speed(),
+		Accuracy:           c.accuracyValue(),
+		Flashlight:         c.flashlight(),
+		EffectiveMissCount: c.effectiveMissCount,
+	}
+
+	result.PP = math.Pow(
+		math.Pow(result.Aim, 1.1)+
+			math.Pow(result.Speed, 1.1)+
+			math.Pow(result.Accuracy, 1.1)+
+			math.Pow(result.Flashlight, 1.1),
+		1.0/1.1,
+	) * multiplier
+
+	return result
+}
+
+type osuPerformance struct {
+	beatmap            PerformanceBeatmap
+	attributes         OsuDifficultyAttributes
+	mods               Mod
+	combo              float64
+	statistics         ScoreStatistics
+	totalHits          float64
+	accuracy           float64
+	ar                 float64
+	od                 float64
+	greatHitWindow     float64
+	okHitWindow        float64
+	mehHitWindow       float64
+	effectiveMissCount float64
+	speedDeviation     float64
+	hasSpeedDeviation  bool
+}
+
+// calculateHitWindows derives the hit windows in milliseconds of gameplay time from the rate-adjusted overall difficulty.
+func (c *osuPerformance) calculateHitWindows() {
+	rate := c.mods.ClockRate()
+
+	c.greatHitWindow = 80 - 6*c.od
+	od := (80 - c.greatHitWindow*rate) / 6
+
+	c.okHitWindow = (140 - 8*od) / rate
+	c.mehHitWindow = (200 - 10*od) / rate
+}
+
+func (c *osuPerformance) calculateEffectiveMissCount() float64 {
+	missCount := float64(c.statistics.CountMiss)
+
+	if c.beatmap.CountSliders > 0 {
+		// Dropped slider ends do not break combo, and without their count 10% of sliders are assumed to be dropped.
+		fullComboThreshold := float64(c.beatmap.MaxCombo) - 0.1*float64(c.beatmap.CountSliders)
+		if c.combo < fullComboThreshold {
+			missCount = fullComboThreshold / math.Max(1, c.combo)
+		}
+
+		missCount = math.Min(missCount, float64(c.statistics.Count100+c.statistics.Count50+c.statistics.CountMiss))
+	}
+
+	return math.Min(math.Max(float64(c.statistics.CountMiss), missCount), c.totalHits)
+}
+
+func (c *osuPerformance) lengthBonus() float64 {
+	bonus := 0.95 + 0.4*math.Min(1, c.totalHits/2000)
+	if c.totalHits > 2000 {
+		bonus += math.Log10(c.totalHits/2000) * 0.5
+	}
+	return bonus
+}
+
+func (c *osuPerformance) missPenalty(difficultStrainCount float64) float64 {
+	// Attributes calculated before strain counts were added have none, so fall back to the penalty they replaced.
+	if difficultStrainCount <= 1 {
+		return 0.97 * math.Pow(1-math.Pow(c.effectiveMissCount/c.totalHits, 0.775), c.effectiveMissCount)
+	}
+
+	return 0.96 / (c.effectiveMissCount/(4*math.Pow(math.Log(difficultStrainCount), 0.94)) + 1)
+}
+
+func osuDifficultyToPerformance(difficulty float64) float64 {
+	return math.Pow(5*math.Max(1, difficulty/0.0675)-4, 3) / 100000
+}
+
+func (c *osuPerformance) aim() float64 {
+	if c.mods&AP != 0 {
+		return 0
+	}
+
+	difficulty := float64(c.attributes.AimDifficulty)
+
+	// Assume the difficult sliders were the ones not followed properly, up to the non-great judgements and lost combo.
+	difficultSliders := float64(c.attributes.AimDifficultSliderCount)
+	if c.beatmap.CountSliders > 0 && difficultSliders > 0 {
+		sliderFactor := float64(c.attributes.SliderFactor)
+		dropped := math.Min(float64(c.statistics.Count100+c.statistics.Count50+c.statistics.CountMiss), float64(c.beatmap.MaxCombo)-c.combo)
+		droppedDifficultSliders := math.Max(0, math.Min(dropped, difficultSliders))
+		difficulty *= (1-sliderFactor)*math.Pow(1-droppedDifficultSliders/difficultSliders, 3) + sliderFactor
+	}
+
+	value := osuDifficultyToPerformance(difficulty)
+
+	lengthBonus := c.lengthBonus()
+	value *= lengthBonus
+
+	if c.effectiveMissCount > 0 {
+		value *= c.missPenalty(float64(c.attributes.AimDifficultStrainCount))
+	}
+
+	arFactor := 0.0
+	if c.ar > 10.33 {
+		arFactor = 0.3 * (c.ar - 10.33)
+	} else if c.ar < 8 {
+		arFactor = 0.05 * (8 - c.ar)
+	}
+
+	if c.mods&RX != 0 {
+		arFactor = 0
+	}
+
+	value *= 1 + arFactor*lengthBonus
+
+	if c.mods&HD != 0 {
+		value *= 1 + 0.04*(12-c.ar)
+	}
+
+	value *= c.accuracy
+	value *= 0.98 + math.Pow(math.Max(0, c.od), 2)/2500
+
+	return value
+}
+
+func (c *osuPerformance) speed() float64 {
+	if c.mods&RX != 0 || !c.hasSpeedDeviation {
+		return 0
+	}
+
+	value := osuDifficultyToPerformance(float64(c.attributes.SpeedDifficulty))
+
+	lengthBonus := c.lengthBonus()
+	value *= lengthBonus
+
+	if c.effectiveMissCount > 0 {
+		value *= c.missPenalty(float64(c.attributes.SpeedDifficultStrainCount))
+	}
+
+	arFactor := 0.0
+	if c.ar > 10.33 && c.mods&AP == 0 {
+		arFactor = 0.3 * (c.ar - 10.33)
+	}
+
+	value *= 1 + arFactor*lengthBonus
+
+	if c.mods&HD != 0 {
+		value *= 1 + 0.04*(12-c.ar)
+	}
+
+	value *= c.speedHighDeviationNerf()
+
+	// Only the notes that contribute to speed difficulty are considered for its accuracy scaling.
+	speedNoteCount := float64(c.attributes.SpeedNoteCount)
+	relevantTotalDiff := math.Max(0, c.totalHits-speedNoteCount)
+	relevantCountGreat := math.Max(0, float64(c.statistics.Count300)-relevantTotalDiff)
+	relevantCountOk := math.Max(0, float64(c.statistics.Count100)-math.Max(0, relevantTotalDiff-float64(c.statistics.Count300)))
+	relevantCountMeh := math.Max(0, float64(c.statistics.Count50)-math.Max(0, relevantTotalDiff-float64(c.statistics.Count300+c.statistics.Count100)))
+
+	relevantAccuracy := 0.0
+	if speedNoteCount > 0 {
+		relevantAccuracy = (relevantCountGreat*6 + relevantCountOk*2 + relevantCountMeh) / (speedNoteCount * 6)
+	}
+
+	value *= math.Pow((c.accuracy+relevantAccuracy)/2, (14.5-c.od)/2)
+
+	return value
+}
+
+// calculateSpeedDeviation estimates the tapping deviation on speed notes, assuming every mistake was made on them.
+func (c *osuPerformance) calculateSpeedDeviation() (float64, bool) {
+	if c.statistics.Count300+c.statistics.Count100+c.statistics.Count50 == 0 {
+		return 0, false
+	}
+
+	// Notes that do not contribute to speed difficulty still count slightly.
+	speedNoteCount := float64(c.attributes.SpeedNoteCount)
+	speedNoteCount += (c.totalHits - speedNoteCount) * 0.1
+
+	relevantCountMiss := math.Min(float64(c.statistics.CountMiss), speedNoteCount)
+	relevantCountMeh := math.Min(float64(c.statistics.Count50), speedNoteCount-relevantCountMiss)
+	relevantCountOk := math.Min(float64(c.statistics.Count100), speedNoteCount-relevantCountMiss-relevantCountMeh)
+	relevantCountGreat := math.Max(0, speedNoteCount-relevantCountMiss-relevantCountMeh-relevantCountOk)
+
+	return c.calculateDeviation(relevantCountGreat, relevantCountOk, relevantCountMeh)
+}
+
+// calculateDeviation returns an upper bound of the hit error deviation we can be 99% confident in,
+// assuming greats and oks are normally distributed and mehs uniformly distributed.
+func (c *osuPerformance) calculateDeviation(countGreat, countOk, countMeh float64) (float64, bool) {
+	if countGreat+countOk+countMeh <= 0 {
+		return 0, false
+	}
+
+	// The 99% one-tailed critical value of the normal distribution.
+	const z = 2.32634787404
+
+	n := math.Max(1, countGreat+countOk)
+	p := countGreat / n
+
+	// We can be 99% confident that the proportion of greats is at least this value.
+	pLowerBound := (n*p+z*z/2)/(n+z*z) - z/(n+z*z)*math.Sqrt(n*p*(1-p)+z*z/4)
+
+	deviation := c.greatHitWindow / (math.Sqrt2 * math.Erfinv(pLowerBound))
+
+	randomValue := math.Sqrt(2/math.Pi) * c.okHitWindow * math.Exp(-0.5*math.Pow(c.okHitWindow/deviation, 2)) /
+		(deviation * math.Erf(c.okHitWindow/(math.Sqrt2*deviation)))
+
+	deviation *= math.Sqrt(1 - randomValue)
+
+	// The deviation approaches this value as the number of greats approaches 0.
+	limitValue := c.okHitWindow / math.Sqrt(3)
+
+	if pLowerBound == 0 || randomValue >= 1 || deviation > limitValue {
+		deviation = limitValue
+	}
+
+	mehVariance := (c.mehHitWindow*c.mehHitWindow + c.okHitWindow*c.mehHitWindow + c.okHitWindow*c.okHitWindow) / 3
+
+	deviation = math.Sqrt(((countGreat+countOk)*math.Pow(deviation, 2) + countMeh*mehVariance) / (countGreat + countOk + countMeh))
+
+	return deviation, true
+}
+
+// speedHighDeviationNerf scales speed pp above what the estimated deviation suggests was tapped properly
+// logarithmically towards the cutoff.
+func (c *osuPerformance) speedHighDeviationNerf() float64 {
+	value := osuDifficultyToPerformance(float64(c.attributes.SpeedDifficulty))
+
+	excessSpeedDifficultyCutoff := 100 + 220*math.Pow(22/c.speedDeviation, 6.5)
+	if value <= excessSpeedDifficultyCutoff {
+		return 1
+	}
+
+	const scale = 50
+	adjusted := scale * (math.Log((value-excessSpeedDifficultyCutoff)/scale+1) + excessSpeedDifficultyCutoff/scale)
+
+	// A deviation of 22, around 220 unstable rate, and below is considered tapped properly.
+	t := 1 - math.Max(0, math.Min(1, (c.speedDeviation-22)/(27-22)))
+	adjusted = adjusted + (value-adjusted)*t
+
+	return adjusted / value
+}
+
+func (c *osuPerformance) accuracyValue() float64 {
+	if c.mods&RX != 0 {
+		return 0
+	}
+
+	// Sliders only have accuracy when their heads are judged, which is the case in ScoreV2.
+	amountHitObjectsWithAccuracy := float64(c.beatmap.CountCircles)
+	if c.mods&SV2 != 0 {
+		amountHitObjectsWithAccuracy += float64(c.beatmap.CountSliders)
+	}
+
+	betterAccuracyPercentage := 0.0
+	if amountHitObjectsWithAccuracy > 0 {
+		betterAccuracyPercentage = ((float64(c.statistics.Count300)-math.Max(0, c.totalHits-amountHitObjectsWithAccuracy))*6 +
+			float64(c.statistics.Count100)*2 +
+			float64(c.statistics.Count50)) / (amountHitObjectsWithAccuracy * 6)
+	}
+
+	betterAccuracyPercentage = math.Max(0, betterAccuracyPercentage)
+
+	value := math.Pow(1.52163, c.od) * math.Pow(betterAccuracyPercentage, 24) * 2.83
+	value *= math.Min(1.15, math.Pow(amountHitObjectsWithAccuracy/1000, 0.3))
+
+	if c.mods&HD != 0 {
+		value *= 1.08
+	}
+
+	if c.mods&FL != 0 {
+		value *= 1.02
+	}
+
+	return value
+}
+
+func (c *osuPerformance) flashlight() float64 {
+	if c.mods&FL == 0 {
+		return 0
+	}
+
+	value := math.Pow(float64(c.attributes.FlashlightDifficulty), 2) * 25
+
+	if c.effectiveMissCount > 0 {
+		value *= 0.97 * math.Pow(1-math.Pow(c.effectiveMissCount/c.totalHits, 0.775), math.Pow(c.effectiveMissCount, 0.875))
+	}
+
+	value *= comboScalingFactor(c.combo, float64(c.beatmap.MaxCombo))
+
+	value *= 0.5 + c.accuracy/2
+	value *= 0.98 + math.Pow(math.Max(0, c.od), 2)/2500
+
+	return value
+}
+
+func comboScalingFactor(combo, maxCombo float64) float64 {
+	if maxCombo <= 0 {
+		return 1
+	}
+	return math.Min(math.Pow(combo, 0.8)/math.Pow(maxCombo, 0.8), 1)
+}
+
+// CalculateTaikoPerformance returns the performance points of an osu!taiko play using the formula of lazer's
+// TaikoPerformanceCalculator, which scales difficulty and accuracy by the unstable rate estimated from the hit counts.
+func CalculateTaikoPerformance(beatmap PerformanceBeatmap, attributes TaikoDifficultyAttributes, score PerformanceScore) PerformanceAttributes {
+	// Drumrolls and swells give no judgements, so only hit circles count.
+	totalHits := beatmap.CountCircles
+
+	var statistics ScoreStatistics
+	if score.Statistics != nil {
+		statistics = *score.Statistics
+		totalHits = statistics.Count300 + statistics.Count100 + statistics.CountMiss
+	} else {
+		misses := min(score.Misses, totalHits)
+		remaining := totalHits - misses
+		count100 := clampCount(2*(float64(remaining)-score.accuracy()*float64(totalHits)), remaining)
+		statistics = ScoreStatistics{Count300: remaining - count100, Count100: count100, CountMiss: misses}
+	}
+
+	if totalHits == 0 {
+		return PerformanceAttributes{}
+	}
+
+	starRating := beatmap.StarRating
+	monoStaminaFactor := float64(attributes.MonoStaminaFactor)
+	estimatedUnstableRate, hasUnstableRate := taikoDeviationUpperBound(statistics.Count300, totalHits, float64(attributes.GreatHitWindow))
+	estimatedUnstableRate *= 10
+
+	effectiveMissCount := 0.0
+	if totalSuccessfulHits := statistics.Count300 + statistics.Count100 + statistics.Count50; totalSuccessfulHits > 0 {
+		effectiveMissCount = math.Max(1, 1000/float64(totalSuccessfulHits)) * float64(statistics.CountMiss)
+	}
+
+	multiplier := 1.13
+	if score.Mods&HD != 0 && !beatmap.Convert {
+		multiplier *= 1.075
+	}
+	if score.Mods&EZ != 0 {
+		multiplier *= 0.950
+	}
+
+	baseDifficulty := 5*math.Max(1, starRating/0.115) - 4
+	difficulty := math.Min(math.Pow(baseDifficulty, 3)/69052.51, math.Pow(baseDifficulty, 2.25)/1250)
+	difficulty *= 1 + 0.10*math.Max(0, starRating-10)
+
+	lengthBonus := 1 + 0.1*math.Min(1, float64(totalHits)/1500)
+	difficulty *= lengthBonus
+	difficulty *= math.Pow(0.986, effectiveMissCount)
+
+	if score.Mods&EZ != 0 {
+		difficulty *= 0.90
+	}
+	if score.Mods&HD != 0 {
+		difficulty *= 1.025
+	}
+	if score.Mods&FL != 0 {
+		difficulty *= math.Max(1, 1.050-math.Min(monoStaminaFactor/50, 1)*lengthBonus)
+	}
+
+	accuracyValue := 0.0
+	if hasUnstableRate {
+		// Accuracy is scaled more harshly on maps made almost entirely of single coloured streams.
+		accScalingExponent := 2 + monoStaminaFactor
+		accScalingShift := 500 - 100*(monoStaminaFactor*3)
+		difficulty *= math.Pow(math.Erf(accScalingShift/(math.Sqrt2*estimatedUnstableRate)), accScalingExponent)
+
+		accuracyValue = math.Pow(70/estimatedUnstableRate, 1.1) * math.Pow(starRating, 0.4) * 100
+
+		// Hidden and flashlight together make the map much harder to read.
+		if score.Mods&HD != 0 && score.Mods&FL != 0 && !beatmap.Convert {
+			accuracyValue *= math.Max(1, 1.05*math.Min(1.15, math.Pow(float64(totalHits)/1500, 0.3)))
+		}
+	} else {
+		difficulty = 0
+	}
+
+	return PerformanceAttributes{
+		PP:                 math.Pow(math.Pow(difficulty, 1.1)+math.Pow(accuracyValue, 1.1), 1.0/1.1) * multiplier,
+		Accuracy:           accuracyValue,
+		Difficulty:         difficulty,
+		EffectiveMissCount: effectiveMissCount,
+	}
+}
+
+// taikoDeviationUpperBound returns the hit error deviation we can be 99% confident is not exceeded,
+// assuming hits are normally distributed around the great hit window.
+func taikoDeviationUpperBound(countGreat, totalHits int, greatHitWindow float64) (float64, bool) {
+	if countGreat == 0 || greatHitWindow <= 0 {
+		return 0, false
+	}
+
+	// The 99% one-tailed critical value of the normal distribution.
+	const z = 2.32634787404
+
+	n := float64(totalHits)
+	p := float64(countGreat) / n
+
+	pLowerBound := (n*p+z*z/2)/(n+z*z) - z/(n+z*z)*math.Sqrt(n*p*(1-p)+z*z/4)
+
+	return greatHitWindow / (math.Sqrt2 * math.Erfinv(pLowerBound)), true
+}
+
+// CalculateFruitsPerformance returns the performance points of an osu!catch play using the formula of lazer's
+// CatchPerformanceCalculator.
+// Without Statistics the accuracy is used as is, since tiny droplet counts are not known.
+func CalculateFruitsPerformance(beatmap PerformanceBeatmap, attributes FruitsDifficultyAttributes, score PerformanceScore) PerformanceAttributes {
+	var statistics ScoreStatistics
+	accuracy := score.accuracy()
+
+	if score.Statistics != nil {
+		statistics = *score.Statistics
+		total := statistics.Count300 + statistics.Count100 + statistics.Count50 + statistics.CountKatu + statistics.CountMiss
+		if total > 0 {
+			accuracy = float64(statistics.Count300+statistics.Count100+statistics.Count50) / float64(total)
+		}
+	} else {
+		misses := min(score.Misses, beatmap.MaxCombo)
+		statistics = ScoreStatistics{Count300: beatmap.MaxCombo - misses, CountMiss: misses}
+	}
+
+	// Fruits, droplets and misses are the judgements that affect combo.
+	totalComboHits := float64(statistics.Count300 + statistics.Count100 + statistics.CountMiss)
+	if totalComboHits == 0 {
+		return PerformanceAttributes{}
+	}
+
+	combo := float64(score.combo(beatmap.MaxCombo))
+	ar := float64(attributes.ApproachRate)
+
+	value := math.Pow(5*math.Max(1, beatmap.StarRating/0.0049)-4, 2) / 100000
+
+	lengthBonus := 0.95 + 0.3*math.Min(1, totalComboHits/2500)
+	if totalComboHits > 2500 {
+		lengthBonus += math.Log10(totalComboHits/2500) * 0.475
+	}
+	value *= lengthBonus
+
+	value *= math.Pow(0.97, float64(statistics.CountMiss))
+	value *= comboScalingFactor(combo, float64(beatmap.MaxCombo))
+
+	arFactor := 1.0
+	if ar > 9 {
+		arFactor += 0.1 * (ar - 9)
+	}
+	if ar > 10 {
+		arFactor += 0.1 * (ar - 10)
+	} else if ar < 8 {
+		arFactor += 0.025 * (8 - ar)
+	}
+	value *= arFactor
+
+	if score.Mods&HD != 0 {
+		if ar <= 10 {
+			value *= 1.05 + 0.075*(10-ar)
+		} else {
+			value *= 1.01 + 0.04*(11-math.Min(11, ar))
+		}
+	}
+
+	if score.Mods&FL != 0 {
+		value *= 1.35 * lengthBonus
+	}
+
+	value *= math.Pow(accuracy, 5.5)
+
+	if score.Mods&NF != 0 {
+		value *= math.Max(0.90, 1-0.02*float64(statistics.CountMiss))
+	}
+
+	return PerformanceAttributes{
+		PP:                 value,
+		Difficulty:         value,
+		EffectiveMissCount: float64(statistics.CountMiss),
+	}
+}
+
+// CalculateManiaPerformance returns the performance points of an osu!mania play using the formula of lazer's
+// ManiaPerformanceCalculator.
+// Perfects are read from CountGeki and goods from CountKatu.
+func CalculateManiaPerformance(beatmap PerformanceBeatmap, attributes ManiaDifficultyAttributes, score PerformanceScore) PerformanceAttributes {
+	totalHits := beatmap.CountCircles + beatmap.CountSliders
+
+	var statistics ScoreStatistics
+	if score.Statistics != nil {
+		statistics = *score.Statistics
+		totalHits = statistics.CountGeki + statistics.Count300 + statistics.CountKatu + statistics.Count100 + statistics.Count50 + statistics.CountMiss
+	} else {
+		misses := min(score.Misses, totalHits)
+		remaining := totalHits - misses
+		countKatu := clampCount(3*(float64(remaining)-score.accuracy()*float64(totalHits)), remaining)
+		statistics = ScoreStatistics{CountGeki: remaining - countKatu, CountKatu: countKatu, CountMiss: misses}
+	}
+
+	if totalHits == 0 {
+		return PerformanceAttributes{}
+	}
+
+	customAccuracy := float64(statistics.CountGeki*320+statistics.Count300*300+statistics.CountKatu*200+statistics.Count100*100+statistics.Count50*50) /
+		float64(totalHits*320)
+
+	multiplier := 8.0
+	if score.Mods&NF != 0 {
+		multiplier *= 0.75
+	}
+	if score.Mods&EZ != 0 {
+		multiplier *= 0.5
+	}
+
+	difficulty := math.Pow(math.Max(beatmap.StarRating-0.15, 0.05), 2.2) *
+		math.Max(0, 5*customAccuracy-4) *
+		(1 + 0.1*math.Min(1, float64(totalHits)/1500))
+
+	return PerformanceAttributes{
+		PP:                 difficulty * multiplier,
+		Difficulty:         difficulty,
+		EffectiveMissCount: float64(statistics.CountMiss),
+	}
+}
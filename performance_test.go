@@ -0,0 +1,186 @@
+package gosu
+
+import (
+	"math"
+	"testing"
+)
+
+func withinTolerance(got, want float64) bool {
+	return math.Abs(got-want) <= 1e-6*math.Max(1, math.Abs(want))
+}
+
+func TestCalculateOsuPerformance(t *testing.T) {
+	beatmap := PerformanceBeatmap{StarRating: 6.2, MaxCombo: 900, CountCircles: 500, CountSliders: 200, CountSpinners: 3}
+	attributes := OsuDifficultyAttributes{
+		AimDifficulty:             3.2,
+		AimDifficultSliderCount:   75.5,
+		SpeedDifficulty:           3.0,
+		SpeedNoteCount:            380.5,
+		SliderFactor:              0.98,
+		AimDifficultStrainCount:   120.5,
+		SpeedDifficultStrainCount: 90.25,
+		ApproachRate:              9.3,
+		OverallDifficulty:         8.5,
+	}
+
+	fastAttributes := attributes
+	fastAttributes.ApproachRate = 11
+	fastAttributes.OverallDifficulty = 10.11
+	fastAttributes.FlashlightDifficulty = 2.1
+
+	tests := []struct {
+		name       string
+		attributes OsuDifficultyAttributes
+		score      PerformanceScore
+		want       PerformanceAttributes
+	}{
+		{
+			name:       "full combo",
+			attributes: attributes,
+			score: PerformanceScore{
+				Combo:      ptr(900),
+				Statistics: &ScoreStatistics{Count300: 700},
+			},
+			want: PerformanceAttributes{PP: 348.31699293998474, Aim: 139.17123121359677, Speed: 113.2722220159122, Accuracy: 81.49002755423238},
+		},
+		{
+			name:       "combo break",
+			attributes: attributes,
+			score: PerformanceScore{
+				Mods:       HD,
+				Combo:      ptr(400),
+				Statistics: &ScoreStatistics{Count300: 650, Count100: 40, Count50: 5, CountMiss: 8},
+			},
+			want: PerformanceAttributes{PP: 176.6466691084545, Aim: 90.35277521874094, Speed: 63.44306475799605, Accuracy: 12.642644503421637, EffectiveMissCount: 8},
+		},
+		{
+			name:       "rate change and flashlight",
+			attributes: fastAttributes,
+			score: PerformanceScore{
+				Mods:       HD | DT | FL,
+				Combo:      ptr(850),
+				Statistics: &ScoreStatistics{Count300: 680, Count100: 20, CountMiss: 3},
+			},
+			want: PerformanceAttributes{PP: 431.02672821117926, Aim: 137.23613053472891, Speed: 108.37952365307378, Accuracy: 79.51950409982294, Flashlight: 99.20366620187978, EffectiveMissCount: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateOsuPerformance(beatmap, tt.attributes, tt.score)
+
+			for _, value := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"PP", got.PP, tt.want.PP},
+				{"Aim", got.Aim, tt.want.Aim},
+				{"Speed", got.Speed, tt.want.Speed},
+				{"Accuracy", got.Accuracy, tt.want.Accuracy},
+				{"Flashlight", got.Flashlight, tt.want.Flashlight},
+				{"EffectiveMissCount", got.EffectiveMissCount, tt.want.EffectiveMissCount},
+			} {
+				if !withinTolerance(value.got, value.want) {
+					t.Errorf("%s = %v, want %v", value.name, value.got, value.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateTaikoPerformance(t *testing.T) {
+	tests := []struct {
+		name       string
+		beatmap    PerformanceBeatmap
+		attributes TaikoDifficultyAttributes
+		score      PerformanceScore
+		want       PerformanceAttributes
+	}{
+		{
+			name:       "full combo",
+			beatmap:    PerformanceBeatmap{StarRating: 5.2, CountCircles: 1200},
+			attributes: TaikoDifficultyAttributes{MonoStaminaFactor: 0.1, GreatHitWindow: 25},
+			score:      PerformanceScore{},
+			want:       PerformanceAttributes{PP: 334.126388449824, Difficulty: 164.50932748618044, Accuracy: 150.3778324822641},
+		},
+		{
+			name:       "misses",
+			beatmap:    PerformanceBeatmap{StarRating: 5.2, CountCircles: 1200},
+			attributes: TaikoDifficultyAttributes{MonoStaminaFactor: 0.1, GreatHitWindow: 25},
+			score:      PerformanceScore{Mods: HD, Statistics: &ScoreStatistics{Count300: 1100, Count100: 90, CountMiss: 10}},
+			want:       PerformanceAttributes{PP: 260.16102723079575, Difficulty: 145.76375065940718, Accuracy: 81.4123702254723, EffectiveMissCount: 10},
+		},
+		{
+			name:       "hidden and flashlight",
+			beatmap:    PerformanceBeatmap{StarRating: 6.1, CountCircles: 1485},
+			attributes: TaikoDifficultyAttributes{MonoStaminaFactor: 0.3, GreatHitWindow: 20.5},
+			score:      PerformanceScore{Mods: HD | FL, Statistics: &ScoreStatistics{Count300: 1400, Count100: 80, CountMiss: 5}},
+			want:       PerformanceAttributes{PP: 419.51561825199394, Difficulty: 240.19792877034288, Accuracy: 125.80235130935905, EffectiveMissCount: 5},
+		},
+		{
+			name:       "no greats",
+			beatmap:    PerformanceBeatmap{StarRating: 5.2, CountCircles: 100},
+			attributes: TaikoDifficultyAttributes{GreatHitWindow: 25},
+			score:      PerformanceScore{Statistics: &ScoreStatistics{Count100: 100}},
+			want:       PerformanceAttributes{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateTaikoPerformance(tt.beatmap, tt.attributes, tt.score)
+
+			for _, value := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"PP", got.PP, tt.want.PP},
+				{"Difficulty", got.Difficulty, tt.want.Difficulty},
+				{"Accuracy", got.Accuracy, tt.want.Accuracy},
+				{"EffectiveMissCount", got.EffectiveMissCount, tt.want.EffectiveMissCount},
+			} {
+				if !withinTolerance(value.got, value.want) {
+					t.Errorf("%s = %v, want %v", value.name, value.got, value.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateFruitsPerformance(t *testing.T) {
+	beatmap := PerformanceBeatmap{StarRating: 5.5, MaxCombo: 1000}
+	score := PerformanceScore{
+		Mods:       HD,
+		Combo:      ptr(800),
+		Statistics: &ScoreStatistics{Count300: 950, Count100: 40, Count50: 200, CountKatu: 5, CountMiss: 10},
+	}
+
+	got := CalculateFruitsPerformance(beatmap, FruitsDifficultyAttributes{ApproachRate: 9.5}, score)
+	if want := 221.2713289020423; !withinTolerance(got.PP, want) {
+		t.Errorf("PP = %v, want %v", got.PP, want)
+	}
+}
+
+func TestCalculateManiaPerformance(t *testing.T) {
+	statistics := ScoreStatistics{CountGeki: 900, Count300: 250, CountKatu: 40, Count100: 8, Count50: 2}
+
+	tests := []struct {
+		name       string
+		starRating float64
+		mods       Mod
+		want       float64
+	}{
+		{"no mods", 5.0, 0, 234.79488308375537},
+		{"no fail", 4.2, NF, 118.4456505453033},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beatmap := PerformanceBeatmap{StarRating: tt.starRating}
+			got := CalculateManiaPerformance(beatmap, ManiaDifficultyAttributes{}, PerformanceScore{Mods: tt.mods, Statistics: &statistics})
+			if !withinTolerance(got.PP, tt.want) {
+				t.Errorf("PP = %v, want %v", got.PP, tt.want)
+			}
+		})
+	}
+}
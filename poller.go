@@ -0,0 +1,130 @@
+package gosu
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrInvalidInterval = errors.New("invalid interval")
+
+// runPollLoop calls poll every interval and sends the events it returns until the context is cancelled.
+// Events returned alongside an error are still sent before the loop stops with that error.
+func runPollLoop[E any](ctx context.Context, interval time.Duration, events chan<- E, poll func() ([]E, error)) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		newEvents, err := poll()
+
+		for _, event := range newEvents {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// roundRobin is a set of keys handed out one at a time in the order they were added.
+type roundRobin[K comparable] struct {
+	mu   sync.Mutex
+	keys []K
+	next int
+}
+
+func (r *roundRobin[K]) add(key K) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, k := range r.keys {
+		if k == key {
+			return
+		}
+	}
+
+	r.keys = append(r.keys, key)
+}
+
+func (r *roundRobin[K]) remove(key K) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, k := range r.keys {
+		if k == key {
+			r.keys = append(r.keys[:i], r.keys[i+1:]...)
+			if r.next > i {
+				r.next--
+			}
+			return
+		}
+	}
+}
+
+func (r *roundRobin[K]) list() []K {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]K(nil), r.keys...)
+}
+
+// nextKey returns the key after the one returned by the previous call, wrapping around at the end.
+func (r *roundRobin[K]) nextKey() (K, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.keys) == 0 {
+		var zero K
+		return zero, false
+	}
+
+	if r.next >= len(r.keys) {
+		r.next = 0
+	}
+
+	key := r.keys[r.next]
+	r.next++
+
+	return key, true
+}
+
+// memoryStore implements the Load and Save methods of the in-memory stores.
+type memoryStore[K comparable, V any] struct {
+	mu     sync.Mutex
+	values map[K]*V
+}
+
+func (s *memoryStore[K, V]) Load(key K) (*V, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.values[key], nil
+}
+
+func (s *memoryStore[K, V]) Save(key K, value *V) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.values == nil {
+		s.values = make(map[K]*V)
+	}
+
+	s.values[key] = value
+	return nil
+}
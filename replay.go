@@ -0,0 +1,560 @@
+package gosu
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ulikunitz/xz/lzma"
+)
+
+// ReplayKeys is the bitmask of inputs held during a replay frame.
+// In mania the X coordinate of a frame holds the pressed columns instead.
+type ReplayKeys int
+
+const (
+	ReplayKeyM1 ReplayKeys = 1 << iota
+	ReplayKeyM2
+	ReplayKeyK1
+	ReplayKeyK2
+	ReplayKeySmoke
+)
+
+// firstOnlineIDReplayVersion is the first replay version that stores the online score ID,
+// and firstLongOnlineIDReplayVersion the first that stores it as an int64 instead of an int32.
+const (
+	firstOnlineIDReplayVersion     = 20121008
+	firstLongOnlineIDReplayVersion = 20140721
+)
+
+// firstLazerReplayVersion is the first replay version that carries the lazer score info trailer.
+const firstLazerReplayVersion = 30000001
+
+// replaySeedFrameDelta marks the frame that stores the RNG seed instead of an input.
+const replaySeedFrameDelta = -12345
+
+// maxReplayFieldLength bounds the length of a single string or byte array in a replay.
+const maxReplayFieldLength = 64 << 20
+
+// windowsEpochTicks is the number of 100ns ticks between 0001-01-01 and the Unix epoch.
+const windowsEpochTicks = 621355968000000000
+
+var ErrInvalidReplay = errors.New("invalid replay")
+
+type LifeBarPoint struct {
+	Time int
+	HP   float64
+}
+
+type ReplayFrame struct {
+	// Delta is the time in milliseconds since the previous frame.
+	Delta int
+	// Time is the absolute time of the frame in milliseconds.
+	Time int
+	X    float32
+	Y    float32
+	Keys ReplayKeys
+}
+
+// ReplayScoreInfo is the additional score data written by lazer at the end of a replay.
+type ReplayScoreInfo struct {
+	OnlineID              int64          `json:"online_id"`
+	Mods                  []APIMod       `json:"mods"`
+	Statistics            map[string]int `json:"statistics"`
+	MaximumStatistics     map[string]int `json:"maximum_statistics"`
+	ClientVersion         string         `json:"client_version"`
+	Rank                  Grade          `json:"rank"`
+	UserID                int            `json:"user_id"`
+	TotalScoreWithoutMods *int64         `json:"total_score_without_mods"`
+}
+
+type Replay struct {
+	Mode       Ruleset
+	Version    int
+	BeatmapMD5 string
+	PlayerName string
+	ReplayMD5  string
+	Statistics ScoreStatistics
+	Score      int
+	MaxCombo   int
+	Perfect    bool
+	Mods       Mod
+	LifeBar    []LifeBarPoint
+	Timestamp  time.Time
+	Frames     []ReplayFrame
+	Seed       *int
+	// OnlineScoreID is 0 for replays older than version 20121008, which do not store it.
+	OnlineScoreID int64
+	// TargetPracticeAccuracy is only present when the TR mod is enabled.
+	TargetPracticeAccuracy *float64
+	ScoreInfo              *ReplayScoreInfo
+}
+
+// ParseReplay reads a replay in the .osr format.
+func ParseReplay(r io.Reader) (*Replay, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	rd := &osrReader{r: bytes.NewReader(data)}
+	replay := &Replay{}
+
+	replay.Mode = Ruleset(rd.byte())
+	replay.Version = int(rd.int32())
+	replay.BeatmapMD5 = rd.string()
+	replay.PlayerName = rd.string()
+	replay.ReplayMD5 = rd.string()
+	replay.Statistics.Count300 = int(rd.int16())
+	replay.Statistics.Count100 = int(rd.int16())
+	replay.Statistics.Count50 = int(rd.int16())
+	replay.Statistics.CountGeki = int(rd.int16())
+	replay.Statistics.CountKatu = int(rd.int16())
+	replay.Statistics.CountMiss = int(rd.int16())
+	replay.Score = int(rd.int32())
+	replay.MaxCombo = int(rd.int16())
+	replay.Perfect = rd.byte() != 0
+	replay.Mods = Mod(rd.int32())
+	lifeBar := rd.string()
+	ticks := rd.int64()
+	frames := rd.bytes()
+
+	if rd.err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReplay, rd.err)
+	}
+
+	switch {
+	case replay.Version >= firstLongOnlineIDReplayVersion:
+		replay.OnlineScoreID = rd.int64()
+	case replay.Version >= firstOnlineIDReplayVersion:
+		replay.OnlineScoreID = int64(rd.int32())
+	}
+
+	if replay.Mods&TR != 0 {
+		accuracy := rd.float64()
+		replay.TargetPracticeAccuracy = &accuracy
+	}
+
+	if rd.err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReplay, rd.err)
+	}
+
+	// Only lazer replays carry the score info, and replays that end before it are still complete.
+	if replay.Version >= firstLazerReplayVersion && rd.r.Len() > 0 {
+		if data := rd.bytes(); len(data) > 0 && rd.err == nil {
+			info, err := decompressReplayData(data)
+			if err != nil {
+				return nil, err
+			}
+
+			replay.ScoreInfo = &ReplayScoreInfo{}
+			if err := json.Unmarshal(info, replay.ScoreInfo); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+			}
+		}
+	}
+
+	if rd.err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReplay, rd.err)
+	}
+
+	replay.LifeBar, err = parseLifeBar(lifeBar)
+	if err != nil {
+		return nil, err
+	}
+
+	replay.Timestamp = ticksToTime(ticks)
+
+	if len(frames) > 0 {
+		data, err := decompressReplayData(frames)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := replay.parseFrames(string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	return replay, nil
+}
+
+// Write encodes the replay in the .osr format.
+func (replay *Replay) Write(w io.Writer) error {
+	frames, err := compressReplayData([]byte(replay.formatFrames()))
+	if err != nil {
+		return err
+	}
+
+	wr := &osrWriter{w: bufio.NewWriter(w)}
+
+	wr.byte(byte(replay.Mode))
+	wr.int32(int32(replay.Version))
+	wr.string(replay.BeatmapMD5)
+	wr.string(replay.PlayerName)
+	wr.string(replay.ReplayMD5)
+	wr.int16(int16(replay.Statistics.Count300))
+	wr.int16(int16(replay.Statistics.Count100))
+	wr.int16(int16(replay.Statistics.Count50))
+	wr.int16(int16(replay.Statistics.CountGeki))
+	wr.int16(int16(replay.Statistics.CountKatu))
+	wr.int16(int16(replay.Statistics.CountMiss))
+	wr.int32(int32(replay.Score))
+	wr.int16(int16(replay.MaxCombo))
+	if replay.Perfect {
+		wr.byte(1)
+	} else {
+		wr.byte(0)
+	}
+	wr.int32(int32(replay.Mods))
+	wr.string(formatLifeBar(replay.LifeBar))
+	wr.int64(timeToTicks(replay.Timestamp))
+	wr.bytes(frames)
+
+	switch {
+	case replay.Version >= firstLongOnlineIDReplayVersion:
+		wr.int64(replay.OnlineScoreID)
+	case replay.Version >= firstOnlineIDReplayVersion:
+		wr.int32(int32(replay.OnlineScoreID))
+	}
+
+	if replay.Mods&TR != 0 {
+		var accuracy float64
+		if replay.TargetPracticeAccuracy != nil {
+			accuracy = *replay.TargetPracticeAccuracy
+		}
+		wr.float64(accuracy)
+	}
+
+	if replay.Version >= firstLazerReplayVersion && replay.ScoreInfo != nil {
+		info, err := json.Marshal(replay.ScoreInfo)
+		if err != nil {
+			return err
+		}
+
+		data, err := compressReplayData(info)
+		if err != nil {
+			return err
+		}
+
+		wr.bytes(data)
+	}
+
+	if wr.err != nil {
+		return wr.err
+	}
+
+	return wr.w.Flush()
+}
+
+func (replay *Replay) parseFrames(data string) error {
+	replay.Frames = nil
+	replay.Seed = nil
+
+	current := 0
+	for _, frame := range strings.Split(data, ",") {
+		if frame == "" {
+			continue
+		}
+
+		parts := strings.Split(frame, "|")
+		if len(parts) != 4 {
+			return fmt.Errorf("%w: malformed frame %q", ErrInvalidReplay, frame)
+		}
+
+		delta, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+		}
+
+		x, err := strconv.ParseFloat(parts[1], 32)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+		}
+
+		y, err := strconv.ParseFloat(parts[2], 32)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+		}
+
+		keys, err := strconv.Atoi(parts[3])
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+		}
+
+		if delta == replaySeedFrameDelta {
+			replay.Seed = &keys
+			continue
+		}
+
+		current += delta
+		replay.Frames = append(replay.Frames, ReplayFrame{
+			Delta: delta,
+			Time:  current,
+			X:     float32(x),
+			Y:     float32(y),
+			Keys:  ReplayKeys(keys),
+		})
+	}
+
+	return nil
+}
+
+func (replay *Replay) formatFrames() string {
+	var result strings.Builder
+
+	for _, frame := range replay.Frames {
+		result.WriteString(strconv.Itoa(frame.Delta))
+		result.WriteByte('|')
+		result.WriteString(strconv.FormatFloat(float64(frame.X), 'f', -1, 32))
+		result.WriteByte('|')
+		result.WriteString(strconv.FormatFloat(float64(frame.Y), 'f', -1, 32))
+		result.WriteByte('|')
+		result.WriteString(strconv.Itoa(int(frame.Keys)))
+		result.WriteByte(',')
+	}
+
+	if replay.Seed != nil {
+		result.WriteString(fmt.Sprintf("%d|0|0|%d,", replaySeedFrameDelta, *replay.Seed))
+	}
+
+	return result.String()
+}
+
+func parseLifeBar(data string) ([]LifeBarPoint, error) {
+	var result []LifeBarPoint
+
+	for _, point := range strings.Split(data, ",") {
+		if point == "" {
+			continue
+		}
+
+		parts := strings.FieldsFunc(point, func(r rune) bool { return r == '|' || r == '/' })
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: malformed life bar point %q", ErrInvalidReplay, point)
+		}
+
+		t, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+		}
+
+		hp, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+		}
+
+		result = append(result, LifeBarPoint{Time: t, HP: hp})
+	}
+
+	return result, nil
+}
+
+func formatLifeBar(points []LifeBarPoint) string {
+	var result strings.Builder
+
+	for _, point := range points {
+		result.WriteString(strconv.Itoa(point.Time))
+		result.WriteByte('|')
+		result.WriteString(strconv.FormatFloat(point.HP, 'f', -1, 64))
+		result.WriteByte(',')
+	}
+
+	return result.String()
+}
+
+func ticksToTime(ticks int64) time.Time {
+	if ticks == 0 {
+		return time.Time{}
+	}
+
+	ticks -= windowsEpochTicks
+	return time.Unix(ticks/10000000, (ticks%10000000)*100).UTC()
+}
+
+func timeToTicks(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()*10000000 + int64(t.Nanosecond()/100) + windowsEpochTicks
+}
+
+func decompressReplayData(data []byte) ([]byte, error) {
+	r, err := lzma.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+	}
+
+	result, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReplay, err)
+	}
+
+	return result, nil
+}
+
+func compressReplayData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w, err := lzma.WriterConfig{Size: int64(len(data))}.NewWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// osrReader reads the little-endian primitives of the .osr format, keeping the first error.
+type osrReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (rd *osrReader) read(data interface{}) {
+	if rd.err != nil {
+		return
+	}
+	rd.err = binary.Read(rd.r, binary.LittleEndian, data)
+}
+
+func (rd *osrReader) byte() byte {
+	var v byte
+	rd.read(&v)
+	return v
+}
+
+func (rd *osrReader) int16() int16 {
+	var v int16
+	rd.read(&v)
+	return v
+}
+
+func (rd *osrReader) int32() int32 {
+	var v int32
+	rd.read(&v)
+	return v
+}
+
+func (rd *osrReader) int64() int64 {
+	var v int64
+	rd.read(&v)
+	return v
+}
+
+func (rd *osrReader) float64() float64 {
+	var v uint64
+	rd.read(&v)
+	return math.Float64frombits(v)
+}
+
+func (rd *osrReader) string() string {
+	switch rd.byte() {
+	case 0x00:
+		return ""
+	case 0x0b:
+	default:
+		if rd.err == nil {
+			rd.err = errors.New("malformed string")
+		}
+		return ""
+	}
+
+	length, err := binary.ReadUvarint(rd.r)
+	if err != nil {
+		rd.err = err
+		return ""
+	}
+
+	return string(rd.readBytes(length))
+}
+
+func (rd *osrReader) bytes() []byte {
+	length := rd.int32()
+	if rd.err != nil || length <= 0 {
+		return nil
+	}
+
+	return rd.readBytes(uint64(length))
+}
+
+// readBytes reads length bytes, failing before allocating when the length exceeds the remaining input.
+func (rd *osrReader) readBytes(length uint64) []byte {
+	if length > maxReplayFieldLength || length > uint64(rd.r.Len()) {
+		rd.err = fmt.Errorf("length %d exceeds the remaining input", length)
+		return nil
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(rd.r, data); err != nil {
+		rd.err = err
+		return nil
+	}
+
+	return data
+}
+
+// osrWriter writes the little-endian primitives of the .osr format, keeping the first error.
+type osrWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (wr *osrWriter) write(data interface{}) {
+	if wr.err != nil {
+		return
+	}
+	wr.err = binary.Write(wr.w, binary.LittleEndian, data)
+}
+
+func (wr *osrWriter) byte(v byte) {
+	wr.write(v)
+}
+
+func (wr *osrWriter) int16(v int16) {
+	wr.write(v)
+}
+
+func (wr *osrWriter) int32(v int32) {
+	wr.write(v)
+}
+
+func (wr *osrWriter) int64(v int64) {
+	wr.write(v)
+}
+
+func (wr *osrWriter) float64(v float64) {
+	wr.write(v)
+}
+
+func (wr *osrWriter) string(v string) {
+	if v == "" {
+		wr.byte(0x00)
+		return
+	}
+
+	wr.byte(0x0b)
+	wr.write(binary.AppendUvarint(nil, uint64(len(v))))
+	wr.write([]byte(v))
+}
+
+func (wr *osrWriter) bytes(v []byte) {
+	wr.int32(int32(len(v)))
+	wr.write(v)
+}
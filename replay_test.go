@@ -0,0 +1,211 @@
+package gosu
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testReplay(version int) *Replay {
+	seed := 42
+
+	return &Replay{
+		Mode:       RulesetOsu,
+		Version:    version,
+		BeatmapMD5: "d41d8cd98f00b204e9800998ecf8427e",
+		PlayerName: "peppy",
+		ReplayMD5:  "0cc175b9c0f1b6a831c399e269772661",
+		Statistics: ScoreStatistics{Count300: 300, Count100: 12, Count50: 3, CountGeki: 60, CountKatu: 8, CountMiss: 1},
+		Score:      1234567,
+		MaxCombo:   456,
+		Mods:       HD | DT,
+		LifeBar:    []LifeBarPoint{{Time: 0, HP: 1}, {Time: 2000, HP: 0.75}},
+		Timestamp:  time.Date(2014, 7, 21, 12, 30, 15, 0, time.UTC),
+		Frames: []ReplayFrame{
+			{Delta: 16, Time: 16, X: 256, Y: 192, Keys: ReplayKeyM1},
+			{Delta: 17, Time: 33, X: 260.5, Y: 190.25, Keys: ReplayKeyM1 | ReplayKeyK1},
+		},
+		Seed:          &seed,
+		OnlineScoreID: 123456789,
+	}
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	accuracy := 0.9875
+	totalScore := int64(987654)
+
+	tests := []struct {
+		name   string
+		replay func() *Replay
+	}{
+		{"int64 online ID", func() *Replay { return testReplay(20150101) }},
+		{"int32 online ID", func() *Replay { return testReplay(20130101) }},
+		{"first int64 version", func() *Replay { return testReplay(firstLongOnlineIDReplayVersion) }},
+		{"first int32 version", func() *Replay { return testReplay(firstOnlineIDReplayVersion) }},
+		{"no online ID", func() *Replay {
+			replay := testReplay(20120101)
+			replay.OnlineScoreID = 0
+			return replay
+		}},
+		{"target practice", func() *Replay {
+			replay := testReplay(20150101)
+			replay.Mods = TR
+			replay.TargetPracticeAccuracy = &accuracy
+			return replay
+		}},
+		{"lazer score info", func() *Replay {
+			replay := testReplay(30000016)
+			replay.ScoreInfo = &ReplayScoreInfo{
+				OnlineID:              4567890123,
+				Mods:                  []APIMod{{Acronym: "DT", Settings: map[string]interface{}{"speed_change": 1.3}}},
+				Statistics:            map[string]int{"great": 300, "miss": 1},
+				MaximumStatistics:     map[string]int{"great": 316},
+				ClientVersion:         "2024.1009.1",
+				Rank:                  "A",
+				UserID:                2,
+				TotalScoreWithoutMods: &totalScore,
+			}
+			return replay
+		}},
+		{"no frames or life bar", func() *Replay {
+			replay := testReplay(20150101)
+			replay.LifeBar = nil
+			replay.Frames = nil
+			replay.Seed = nil
+			return replay
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.replay()
+
+			var buf bytes.Buffer
+			if err := want.Write(&buf); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			got, err := ParseReplay(&buf)
+			if err != nil {
+				t.Fatalf("ParseReplay() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseReplay() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestReplayOnlineScoreIDWidth(t *testing.T) {
+	tests := []struct {
+		version int
+		width   int
+	}{
+		{20120101, 0},
+		{firstOnlineIDReplayVersion, 4},
+		{20140720, 4},
+		{firstLongOnlineIDReplayVersion, 8},
+		{20150101, 8},
+	}
+
+	written := func(version int) int {
+		replay := testReplay(version)
+		replay.OnlineScoreID = 0
+
+		var buf bytes.Buffer
+		if err := replay.Write(&buf); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		return buf.Len()
+	}
+
+	base := written(20120101)
+
+	for _, tt := range tests {
+		if got := written(tt.version) - base; got != tt.width {
+			t.Errorf("version %d: online score ID is %d bytes, want %d", tt.version, got, tt.width)
+		}
+	}
+}
+
+func TestParseReplayTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReplay(20150101).Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data := buf.Bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, ErrInvalidReplay},
+		{"header only", data[:20], ErrInvalidReplay},
+		{"missing online ID", data[:len(data)-8], ErrInvalidReplay},
+		{"inside online ID", data[:len(data)-4], ErrInvalidReplay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseReplay(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseReplay() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseReplayInvalidLength(t *testing.T) {
+	// mode, version and a string marker followed by a ULEB128 length.
+	header := []byte{0, 0x35, 0x8f, 0x33, 0x01, 0x0b}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"string longer than input", append(header, 0x10, 'a')},
+		{"string length out of range", append(header, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01)},
+		{"huge string length", append(header, 0xff, 0xff, 0xff, 0xff, 0x0f)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseReplay(bytes.NewReader(tt.data))
+			if !errors.Is(err, ErrInvalidReplay) {
+				t.Errorf("ParseReplay() error = %v, want %v", err, ErrInvalidReplay)
+			}
+		})
+	}
+}
+
+func TestParseReplayInvalidFramesLength(t *testing.T) {
+	replay := testReplay(20150101)
+	replay.Frames = nil
+	replay.Seed = nil
+
+	var buf bytes.Buffer
+	if err := replay.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// The frames length is the int32 before the compressed frames, which precede the online ID.
+	data := buf.Bytes()
+	frames, err := compressReplayData([]byte(replay.formatFrames()))
+	if err != nil {
+		t.Fatalf("compressReplayData() error = %v", err)
+	}
+	offset := len(data) - 8 - len(frames) - 4
+
+	for _, length := range [][]byte{{0xff, 0xff, 0xff, 0x7f}, {0x00, 0x00, 0x01, 0x00}} {
+		corrupt := append([]byte(nil), data...)
+		copy(corrupt[offset:], length)
+
+		if _, err := ParseReplay(bytes.NewReader(corrupt)); !errors.Is(err, ErrInvalidReplay) {
+			t.Errorf("ParseReplay() with frames length %x error = %v, want %v", length, err, ErrInvalidReplay)
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package gosu
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Score struct {
 	ID         int             `json:"id"`
@@ -20,6 +27,23 @@ type Score struct {
 	Replay     bool            `json:"replay"`
 }
 
+// LegacyMods converts Mods to a bitmask and returns the acronyms that have no legacy equivalent,
+// such as lazer-only mods.
+func (s Score) LegacyMods() (Mod, []string) {
+	var mods Mod
+	var unknown []string
+
+	for _, acronym := range s.Mods {
+		if mod, ok := (APIMod{Acronym: strings.ToUpper(acronym)}).Legacy(); ok {
+			mods |= mod
+		} else {
+			unknown = append(unknown, acronym)
+		}
+	}
+
+	return mods, unknown
+}
+
 type ScoreStatistics struct {
 	Count50   int `json:"count_50"`
 	Count100  int `json:"count_100"`
@@ -33,3 +57,132 @@ type ScoreWeight struct {
 	Percentage float32 `json:"percentage"`
 	PP         float32 `json:"pp"`
 }
+
+type ScoreResponse struct {
+	Score
+	Beatmap struct {
+		Beatmap
+		Checksum *string `json:"checksum"`
+	} `json:"beatmap"`
+	Beatmapset BeatmapsetCompact `json:"beatmapset"`
+	User       UserCompact       `json:"user"`
+}
+
+type ScoresResponse struct {
+	Scores       []Score `json:"scores"`
+	CursorString *string `json:"cursor_string"`
+}
+
+type ScoreRequest struct {
+	client *Client
+	Score  int
+	Mode   *Ruleset
+}
+
+// GetScore returns the details of a score. Setting a mode looks up a legacy score ID for that ruleset.
+func (c *Client) GetScore(score int) *ScoreRequest {
+	return &ScoreRequest{client: c, Score: score}
+}
+
+func (r *ScoreRequest) SetMode(mode Ruleset) *ScoreRequest {
+	r.Mode = &mode
+	return r
+}
+
+func (r *ScoreRequest) Build() (*ScoreResponse, error) {
+	req := r.client.httpClient.R().SetResult(&ScoreResponse{}).SetPathParam("score", strconv.Itoa(r.Score))
+
+	url := "scores/{score}"
+	if r.Mode != nil {
+		req.SetPathParam("ruleset", r.Mode.String())
+		url = "scores/{ruleset}/{score}"
+	}
+
+	resp, err := req.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Result().(*ScoreResponse), nil
+}
+
+type ScoresRequest struct {
+	client       *Client
+	Mode         *Ruleset
+	CursorString *string
+}
+
+// GetScores returns the most recent passed scores across all users.
+func (c *Client) GetScores() *ScoresRequest {
+	return &ScoresRequest{client: c}
+}
+
+func (r *ScoresRequest) SetMode(mode Ruleset) *ScoresRequest {
+	r.Mode = &mode
+	return r
+}
+
+func (r *ScoresRequest) SetCursorString(cursorString string) *ScoresRequest {
+	r.CursorString = &cursorString
+	return r
+}
+
+func (r *ScoresRequest) Build() (*ScoresResponse, error) {
+	req := r.client.httpClient.R().SetResult(&ScoresResponse{})
+
+	if r.Mode != nil {
+		req.SetQueryParam("ruleset", r.Mode.String())
+	}
+
+	if r.CursorString != nil {
+		req.SetQueryParam("cursor_string", *r.CursorString)
+	}
+
+	resp, err := req.Get("scores")
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Result().(*ScoresResponse), nil
+}
+
+type ScoreReplayRequest struct {
+	client *Client
+	Score  int
+	Mode   *Ruleset
+}
+
+// GetScoreReplay returns the replay file of a score. The caller is responsible for closing the returned reader.
+func (c *Client) GetScoreReplay(score int) *ScoreReplayRequest {
+	return &ScoreReplayRequest{client: c, Score: score}
+}
+
+func (r *ScoreReplayRequest) SetMode(mode Ruleset) *ScoreReplayRequest {
+	r.Mode = &mode
+	return r
+}
+
+func (r *ScoreReplayRequest) Build() (io.ReadCloser, error) {
+	req := r.client.httpClient.R().SetDoNotParseResponse(true).SetPathParam("score", strconv.Itoa(r.Score))
+
+	url := "scores/{score}/download"
+	if r.Mode != nil {
+		req.SetPathParam("ruleset", r.Mode.String())
+		url = "scores/{ruleset}/{score}/download"
+	}
+
+	resp, err := req.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		resp.RawBody().Close()
+		if resp.StatusCode() == 404 {
+			return nil, errors.New("not found")
+		}
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status())
+	}
+
+	return resp.RawBody(), nil
+}
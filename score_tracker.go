@@ -0,0 +1,233 @@
+package gosu
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// maxSeenRecentScores limits how many recent score IDs are remembered per user for deduplication.
+const maxSeenRecentScores = 200
+
+// ScoreTrackerEvent is implemented by the events emitted by a ScoreTracker.
+type ScoreTrackerEvent interface {
+	TrackedUser() int
+}
+
+// TopPlayEvent is emitted when a score enters a user's top 100.
+type TopPlayEvent struct {
+	User  int
+	Score UserScore
+	// Index is the position of the score in the user's top plays, starting at 0.
+	Index int
+	// PPDelta is the change in the user's weighted top play pp since the previous poll.
+	PPDelta float64
+}
+
+func (e TopPlayEvent) TrackedUser() int {
+	return e.User
+}
+
+// RecentScoreEvent is emitted for every recent score not seen before.
+type RecentScoreEvent struct {
+	User  int
+	Score UserScore
+}
+
+func (e RecentScoreEvent) TrackedUser() int {
+	return e.User
+}
+
+type TrackedScore struct {
+	ID int
+	PP float32
+}
+
+// TrackedUserState is what a ScoreTracker remembers about a user between polls.
+type TrackedUserState struct {
+	TopScores  []TrackedScore
+	SeenRecent []int
+	LastPolled time.Time
+}
+
+// ScoreTrackerStore persists the state of tracked users.
+type ScoreTrackerStore interface {
+	// Load returns the state of a user, or nil if the user has never been polled.
+	Load(user int) (*TrackedUserState, error)
+	Save(user int, state *TrackedUserState) error
+}
+
+// MemoryScoreTrackerStore keeps tracked user state in memory.
+type MemoryScoreTrackerStore struct {
+	memoryStore[int, TrackedUserState]
+}
+
+func NewMemoryScoreTrackerStore() *MemoryScoreTrackerStore {
+	return &MemoryScoreTrackerStore{}
+}
+
+// ScoreTracker polls the best and recent scores of a set of users and emits new top plays and recent scores.
+type ScoreTracker struct {
+	client       *Client
+	Store        ScoreTrackerStore
+	Mode         *Ruleset
+	IncludeFails bool
+	// RequestsPerMinute is the request budget of the tracker. Every user poll costs two requests.
+	RequestsPerMinute int
+	// OnError receives the errors of failed user polls so Run can move on to the next user.
+	// Without it, Run returns the first error.
+	OnError func(user int, err error)
+
+	users roundRobin[int]
+}
+
+// NewScoreTracker creates a tracker for the users added with AddUser. A nil store keeps their state in memory.
+func (c *Client) NewScoreTracker(store ScoreTrackerStore) *ScoreTracker {
+	if store == nil {
+		store = NewMemoryScoreTrackerStore()
+	}
+
+	return &ScoreTracker{client: c, Store: store, RequestsPerMinute: 60}
+}
+
+func (t *ScoreTracker) SetMode(mode Ruleset) *ScoreTracker {
+	t.Mode = &mode
+	return t
+}
+
+func (t *ScoreTracker) AddUser(user int) {
+	t.users.add(user)
+}
+
+func (t *ScoreTracker) RemoveUser(user int) {
+	t.users.remove(user)
+}
+
+func (t *ScoreTracker) Users() []int {
+	return t.users.list()
+}
+
+func (t *ScoreTracker) scores(user int, scoreType ScoreType) ([]UserScore, error) {
+	req := t.client.GetUserScores(user).SetLimit(100)
+	req.Type = scoreType
+	req.Mode = t.Mode
+
+	if scoreType == ScoreTypeRecent {
+		req.SetIncludeFails(t.IncludeFails)
+	}
+
+	scores, err := req.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return *scores, nil
+}
+
+// PollUser fetches the scores of a user and returns the events since the previous poll.
+// The first poll of a user only records its state.
+func (t *ScoreTracker) PollUser(user int) ([]ScoreTrackerEvent, error) {
+	best, err := t.scores(user, ScoreTypeBest)
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := t.scores(user, ScoreTypeRecent)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := t.Store.Load(user)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &TrackedUserState{LastPolled: time.Now()}
+	for _, score := range best {
+		state.TopScores = append(state.TopScores, TrackedScore{ID: score.ID, PP: score.Pp})
+	}
+
+	var events []ScoreTrackerEvent
+
+	if previous != nil {
+		known := make(map[int]bool, len(previous.TopScores))
+		for _, score := range previous.TopScores {
+			known[score.ID] = true
+		}
+
+		delta := weightedPP(state.TopScores) - weightedPP(previous.TopScores)
+
+		for i, score := range best {
+			if !known[score.ID] {
+				events = append(events, TopPlayEvent{User: user, Score: score, Index: i, PPDelta: delta})
+			}
+		}
+	}
+
+	seen := make(map[int]bool)
+	if previous != nil {
+		for _, id := range previous.SeenRecent {
+			seen[id] = true
+		}
+		state.SeenRecent = append([]int(nil), previous.SeenRecent...)
+	}
+
+	// Recent scores are returned newest first, so walk them backwards to emit in play order.
+	for i := len(recent) - 1; i >= 0; i-- {
+		score := recent[i]
+		if seen[score.ID] {
+			continue
+		}
+
+		if previous != nil {
+			events = append(events, RecentScoreEvent{User: user, Score: score})
+		}
+		state.SeenRecent = append(state.SeenRecent, score.ID)
+	}
+
+	if len(state.SeenRecent) > maxSeenRecentScores {
+		state.SeenRecent = state.SeenRecent[len(state.SeenRecent)-maxSeenRecentScores:]
+	}
+
+	if err := t.Store.Save(user, state); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Run polls the tracked users one at a time, spacing requests to stay within RequestsPerMinute,
+// and sends events on the channel until the context is cancelled.
+func (t *ScoreTracker) Run(ctx context.Context, events chan<- ScoreTrackerEvent) error {
+	requestsPerMinute := t.RequestsPerMinute
+	if requestsPerMinute <= 0 {
+		requestsPerMinute = 60
+	}
+
+	// Each poll makes two requests.
+	return runPollLoop(ctx, 2*time.Minute/time.Duration(requestsPerMinute), events, func() ([]ScoreTrackerEvent, error) {
+		user, ok := t.users.nextKey()
+		if !ok {
+			return nil, nil
+		}
+
+		newEvents, err := t.PollUser(user)
+		if err != nil && t.OnError != nil {
+			t.OnError(user, err)
+			err = nil
+		}
+
+		return newEvents, err
+	})
+}
+
+// weightedPP returns the pp of top plays weighted by 0.95 to the power of their position, as on a user's profile.
+func weightedPP(scores []TrackedScore) float64 {
+	total := 0.0
+
+	for i, score := range scores {
+		total += float64(score.PP) * math.Pow(0.95, float64(i))
+	}
+
+	return total
+}
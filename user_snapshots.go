@@ -0,0 +1,232 @@
+package gosu
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxUsersPerRequest is the number of IDs GetUsers accepts at once.
+const maxUsersPerRequest = 50
+
+// UserSnapshot is the statistics of a user in a ruleset at a point in time.
+type UserSnapshot struct {
+	User       int
+	Mode       Ruleset
+	Time       time.Time
+	Statistics UserStatistics
+}
+
+// SnapshotStore persists user snapshots.
+type SnapshotStore interface {
+	Add(snapshot UserSnapshot) error
+	// Range returns the snapshots of a user in a ruleset taken between from and to inclusive, oldest first.
+	Range(user int, mode Ruleset, from, to time.Time) ([]UserSnapshot, error)
+}
+
+// MemorySnapshotStore keeps user snapshots in memory.
+type MemorySnapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[int][]UserSnapshot
+}
+
+func NewMemorySnapshotStore() *MemorySnapshotStore {
+	return &MemorySnapshotStore{snapshots: make(map[int][]UserSnapshot)}
+}
+
+func (s *MemorySnapshotStore) Add(snapshot UserSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshots := append(s.snapshots[snapshot.User], snapshot)
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+	s.snapshots[snapshot.User] = snapshots
+
+	return nil
+}
+
+func (s *MemorySnapshotStore) Range(user int, mode Ruleset, from, to time.Time) ([]UserSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []UserSnapshot
+
+	for _, snapshot := range s.snapshots[user] {
+		if snapshot.Mode != mode || snapshot.Time.Before(from) || snapshot.Time.After(to) {
+			continue
+		}
+		result = append(result, snapshot)
+	}
+
+	return result, nil
+}
+
+// StatisticsDiff is the change in a user's statistics between two snapshots.
+type StatisticsDiff struct {
+	User int
+	Mode Ruleset
+	From time.Time
+	To   time.Time
+	PP   float64
+	// GlobalRank and CountryRank are the number of ranks gained, negative when ranks were lost.
+	// They are nil when the user was unranked in either snapshot.
+	GlobalRank  *int
+	CountryRank *int
+	PlayCount   int
+	PlayTime    int
+	RankedScore int
+	TotalScore  int
+	TotalHits   int
+	Accuracy    float64
+}
+
+// DiffSnapshots returns the change in statistics from one snapshot to another.
+func DiffSnapshots(from, to UserSnapshot) StatisticsDiff {
+	diff := StatisticsDiff{
+		User:        to.User,
+		Mode:        to.Mode,
+		From:        from.Time,
+		To:          to.Time,
+		PP:          to.Statistics.PP - from.Statistics.PP,
+		PlayCount:   to.Statistics.PlayCount - from.Statistics.PlayCount,
+		PlayTime:    to.Statistics.PlayTime - from.Statistics.PlayTime,
+		RankedScore: to.Statistics.RankedScore - from.Statistics.RankedScore,
+		TotalScore:  to.Statistics.TotalScore - from.Statistics.TotalScore,
+		TotalHits:   to.Statistics.TotalHits - from.Statistics.TotalHits,
+		Accuracy:    to.Statistics.HitAccuracy - from.Statistics.HitAccuracy,
+	}
+
+	if from.Statistics.GlobalRank != nil && to.Statistics.GlobalRank != nil {
+		rank := *from.Statistics.GlobalRank - *to.Statistics.GlobalRank
+		diff.GlobalRank = &rank
+	}
+
+	if from.Statistics.CountryRank != nil && to.Statistics.CountryRank != nil {
+		rank := *from.Statistics.CountryRank - *to.Statistics.CountryRank
+		diff.CountryRank = &rank
+	}
+
+	return diff
+}
+
+// StatisticsRecorder periodically records the statistics of a set of users.
+type StatisticsRecorder struct {
+	client *Client
+	Store  SnapshotStore
+	// Modes limits which rulesets are recorded. All rulesets are recorded when it is empty.
+	Modes    []Ruleset
+	Interval time.Duration
+	// OnError receives recording errors and lets Run carry on at the next interval. If it is nil, Run returns them.
+	OnError func(err error)
+
+	users roundRobin[int]
+}
+
+// NewStatisticsRecorder creates a recorder that snapshots the users added with AddUser every interval.
+// Snapshots go to an in-memory store when store is nil.
+func (c *Client) NewStatisticsRecorder(store SnapshotStore, interval time.Duration) *StatisticsRecorder {
+	if store == nil {
+		store = NewMemorySnapshotStore()
+	}
+
+	return &StatisticsRecorder{client: c, Store: store, Interval: interval}
+}
+
+func (r *StatisticsRecorder) AddUser(user int) {
+	r.users.add(user)
+}
+
+func (r *StatisticsRecorder) RemoveUser(user int) {
+	r.users.remove(user)
+}
+
+func (r *StatisticsRecorder) Users() []int {
+	return r.users.list()
+}
+
+func (r *StatisticsRecorder) recordsMode(mode Ruleset) bool {
+	if len(r.Modes) == 0 {
+		return true
+	}
+
+	for _, m := range r.Modes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Record takes a snapshot of every tracked user, fetching up to 50 users per request.
+func (r *StatisticsRecorder) Record() ([]UserSnapshot, error) {
+	users := r.Users()
+	now := time.Now()
+
+	var result []UserSnapshot
+
+	for start := 0; start < len(users); start += maxUsersPerRequest {
+		end := min(start+maxUsersPerRequest, len(users))
+
+		resp, err := r.client.GetUsers(users[start:end]).Build()
+		if err != nil {
+			return result, err
+		}
+
+		for _, user := range resp.Users {
+			for mode, statistics := range user.StatisticsRulesets {
+				if statistics == nil || !r.recordsMode(mode) {
+					continue
+				}
+
+				snapshot := UserSnapshot{User: user.UserCompact.ID, Mode: mode, Time: now, Statistics: *statistics}
+				if err := r.Store.Add(snapshot); err != nil {
+					return result, err
+				}
+
+				result = append(result, snapshot)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Run records snapshots every Interval until the context is cancelled.
+func (r *StatisticsRecorder) Run(ctx context.Context) error {
+	// Snapshots are only stored, so nothing is sent.
+	return runPollLoop[UserSnapshot](ctx, r.Interval, nil, func() ([]UserSnapshot, error) {
+		_, err := r.Record()
+		if err != nil && r.OnError != nil {
+			r.OnError(err)
+			err = nil
+		}
+
+		return nil, err
+	})
+}
+
+// Diff returns the change in a user's statistics between the first and last snapshots taken in the window.
+// It returns nil if fewer than two snapshots were taken in the window.
+func (r *StatisticsRecorder) Diff(user int, mode Ruleset, from, to time.Time) (*StatisticsDiff, error) {
+	snapshots, err := r.Store.Range(user, mode, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshots) < 2 {
+		return nil, nil
+	}
+
+	diff := DiffSnapshots(snapshots[0], snapshots[len(snapshots)-1])
+	return &diff, nil
+}
+
+// DiffSince returns the change in a user's statistics over the given duration up to now.
+func (r *StatisticsRecorder) DiffSince(user int, mode Ruleset, window time.Duration) (*StatisticsDiff, error) {
+	now := time.Now()
+	return r.Diff(user, mode, now.Add(-window), now)
+}
@@ -1,12 +1,12 @@
 package gosu
 
 import (
-	"reflect"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/mitchellh/mapstructure"
 )
 
 type Playstyle string
@@ -352,19 +352,30 @@ func (r *UserKudosuRequest) Build() (*[]KudosuHistory, error) {
 	return resp.Result().(*[]KudosuHistory), nil
 }
 
+const (
+	maxUserScoresLimit   = 100
+	maxUserScoresResults = 200
+)
+
+var (
+	ErrInvalidLimit  = errors.New("invalid limit")
+	ErrInvalidOffset = errors.New("invalid offset")
+)
+
 type UserScoresRequest struct {
 	client       *Client
 	User         int
 	Type         ScoreType
 	IncludeFails *bool
+	LegacyOnly   *bool
 	Mode         *Ruleset
 	Limit        *int
 	Offset       *int
 }
 
-// GetUserScores returns the scores of a user.
+// GetUserScores returns the scores of a user. Best scores are returned unless another type is set.
 func (c *Client) GetUserScores(user int) *UserScoresRequest {
-	return &UserScoresRequest{client: c, User: user}
+	return &UserScoresRequest{client: c, User: user, Type: ScoreTypeBest}
 }
 
 func (r *UserScoresRequest) Best() *UserScoresRequest {
@@ -387,39 +398,82 @@ func (r *UserScoresRequest) Recent() *UserScoresRequest {
 	return r
 }
 
+func (r *UserScoresRequest) SetType(scoreType ScoreType) *UserScoresRequest {
+	r.Type = scoreType
+	return r
+}
+
+// SetIncludeFails sets whether failed scores are included. It only applies to recent scores.
 func (r *UserScoresRequest) SetIncludeFails(includeFails bool) *UserScoresRequest {
 	r.IncludeFails = &includeFails
 	return r
 }
 
+// SetLegacyOnly sets whether only scores set on stable are returned.
+func (r *UserScoresRequest) SetLegacyOnly(legacyOnly bool) *UserScoresRequest {
+	r.LegacyOnly = &legacyOnly
+	return r
+}
+
+func (r *UserScoresRequest) SetMode(mode Ruleset) *UserScoresRequest {
+	r.Mode = &mode
+	return r
+}
+
+// SetLimit sets the number of scores returned, up to 100.
 func (r *UserScoresRequest) SetLimit(limit int) *UserScoresRequest {
 	r.Limit = &limit
 	return r
 }
 
+// SetOffset sets the number of scores skipped. The API returns at most 200 scores in total across pages.
 func (r *UserScoresRequest) SetOffset(offset int) *UserScoresRequest {
 	r.Offset = &offset
 	return r
 }
 
+func (r *UserScoresRequest) validate() error {
+	limit := 0
+	if r.Limit != nil {
+		limit = *r.Limit
+		if limit < 1 || limit > maxUserScoresLimit {
+			return fmt.Errorf("%d is %w, must be between 1 and %d", limit, ErrInvalidLimit, maxUserScoresLimit)
+		}
+	}
+
+	if r.Offset != nil {
+		offset := *r.Offset
+		if offset < 0 || offset+limit > maxUserScoresResults || offset >= maxUserScoresResults {
+			return fmt.Errorf("%d is %w, offset and limit must not exceed %d", offset, ErrInvalidOffset, maxUserScoresResults)
+		}
+	}
+
+	return nil
+}
+
 func (r *UserScoresRequest) Build() (*[]UserScore, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
 	req := r.client.httpClient.R().SetResult(&[]UserScore{})
 
+	scoreType := r.Type
+	if scoreType == "" {
+		scoreType = ScoreTypeBest
+	}
+
 	req.SetPathParams(map[string]string{
 		"user": strconv.Itoa(r.User),
-		"type": "best",
+		"type": string(scoreType),
 	})
 
-	if reflect.ValueOf(r.Type).IsValid() {
-		req.SetQueryParam("type", string(r.Type))
+	if r.IncludeFails != nil {
+		req.SetQueryParam("include_fails", boolToIntString(*r.IncludeFails))
 	}
 
-	if r.IncludeFails != nil {
-		i := 0
-		if *r.IncludeFails {
-			i = 1
-		}
-		req.SetPathParam("include_fails", strconv.Itoa(i))
+	if r.LegacyOnly != nil {
+		req.SetQueryParam("legacy_only", boolToIntString(*r.LegacyOnly))
 	}
 
 	if r.Mode != nil {
@@ -442,6 +496,13 @@ func (r *UserScoresRequest) Build() (*[]UserScore, error) {
 	return resp.Result().(*[]UserScore), nil
 }
 
+func boolToIntString(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
 type UserBeatmapsRequest struct {
 	client  *Client
 	User    int
@@ -468,6 +529,11 @@ func (c *Client) GetUserBeatmaps(user int, mapType RankStatus) *UserBeatmapsRequ
 	return &UserBeatmapsRequest{client: c, User: user, MapType: maptype}
 }
 
+// GetUserFavouriteBeatmapsets returns the beatmapsets a user has favourited.
+func (c *Client) GetUserFavouriteBeatmapsets(user int) *UserBeatmapsRequest {
+	return &UserBeatmapsRequest{client: c, User: user, MapType: "favourite"}
+}
+
 func (r *UserBeatmapsRequest) SetLimit(limit int) *UserBeatmapsRequest {
 	r.Limit = &limit
 	return r
@@ -571,8 +637,8 @@ func (r *UserRecentActivityRequest) SetOffset(offset int) *UserRecentActivityReq
 	return r
 }
 
-func (r *UserRecentActivityRequest) Build() (*[]EventBase, error) {
-	req := r.client.httpClient.R().SetResult(&[]map[string]interface{}{})
+func (r *UserRecentActivityRequest) Build() (*[]Event, error) {
+	req := r.client.httpClient.R().SetResult(&[]json.RawMessage{})
 
 	req.SetPathParam("user", strconv.Itoa(r.User))
 
@@ -589,21 +655,7 @@ func (r *UserRecentActivityRequest) Build() (*[]EventBase, error) {
 		return nil, err
 	}
 
-	event := resp.Result().(*[]map[string]interface{})
-
-	var result []EventBase
-	config := &mapstructure.DecoderConfig{
-		DecodeHook: mapstructure.StringToTimeHookFunc(time.RFC3339),
-		Result:     &result,
-		TagName:    "json",
-	}
-
-	decoder, err := mapstructure.NewDecoder(config)
-	if err != nil {
-		return nil, err
-	}
-
-	err = decoder.Decode(*event)
+	result, err := decodeEvents(*resp.Result().(*[]json.RawMessage))
 	if err != nil {
 		return nil, err
 	}